@@ -0,0 +1,233 @@
+package aliyun
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseCertificatePem returns the first certificate of a PEM bundle, or nil
+// when the bundle holds no certificate.
+func parseCertificatePem(data string) (*x509.Certificate, error) {
+	certs, err := parseCertificateChain(data)
+	if err != nil || len(certs) == 0 {
+		return nil, err
+	}
+	return certs[0], nil
+}
+
+func parseCertificateChain(data string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return certs, nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("certificate %d: %s", len(certs)+1, err)
+		}
+		certs = append(certs, cert)
+	}
+}
+
+func parsePrivateKey(data string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded private key found")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		switch key := key.(type) {
+		case *rsa.PrivateKey:
+			return key, nil
+		case *ecdsa.PrivateKey:
+			return key, nil
+		case ed25519.PrivateKey:
+			return key, nil
+		}
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+
+	return nil, fmt.Errorf("unsupported PEM block %q, expected a private key", block.Type)
+}
+
+// checkCertificateChain verifies that certs is ordered leaf first, each
+// certificate being signed by the one that follows it, and that every
+// certificate is currently valid.
+func checkCertificateChain(certs []*x509.Certificate, now time.Time) error {
+	if len(certs) == 0 {
+		return fmt.Errorf("no PEM encoded certificate found")
+	}
+
+	for i, cert := range certs {
+		if now.After(cert.NotAfter) {
+			return fmt.Errorf("certificate %d (%s) expired on %s", i+1, cert.Subject.CommonName, cert.NotAfter.UTC().Format(time.RFC3339))
+		}
+		if now.Before(cert.NotBefore) {
+			return fmt.Errorf("certificate %d (%s) is not valid before %s", i+1, cert.Subject.CommonName, cert.NotBefore.UTC().Format(time.RFC3339))
+		}
+		if i+1 < len(certs) {
+			if err := cert.CheckSignatureFrom(certs[i+1]); err != nil {
+				return fmt.Errorf("certificate %d (%s) is not issued by certificate %d (%s), the chain must be ordered from the leaf to the root: %s", i+1, cert.Subject.CommonName, i+2, certs[i+1].Subject.CommonName, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkCertificateForDomain verifies that the private key belongs to the leaf
+// certificate and that the leaf certificate covers domain, which may itself be
+// a wildcard domain. The diagnostics point at ssl_pri or ssl_pub.
+func checkCertificateForDomain(certPem, keyPem, domain string) diag.Diagnostics {
+	leaf, err := parseCertificatePem(certPem)
+	if err != nil || leaf == nil {
+		return nil
+	}
+	key, err := parsePrivateKey(keyPem)
+	if err != nil {
+		return nil
+	}
+
+	if pub, ok := leaf.PublicKey.(interface{ Equal(crypto.PublicKey) bool }); !ok || !pub.Equal(key.Public()) {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Private key does not match the certificate",
+			Detail:        "ssl_pri does not match the public key of the leaf certificate in ssl_pub.",
+			AttributePath: cty.GetAttrPath("ssl_pri"),
+		}}
+	}
+
+	if certificateNamesCover(leaf.DNSNames, domain) {
+		return nil
+	}
+
+	return diag.Diagnostics{{
+		Severity:      diag.Error,
+		Summary:       "Certificate does not cover the domain",
+		Detail:        fmt.Sprintf("ssl_pub does not cover domain_name %s, the leaf certificate is valid for %s.", domain, strings.Join(leaf.DNSNames, ", ")),
+		AttributePath: cty.GetAttrPath("ssl_pub"),
+	}}
+}
+
+// certificateNamesCover reports whether a certificate issued for names is
+// valid for domain. A wildcard name covers a single label and a wildcard
+// domain is only covered by the same wildcard name.
+func certificateNamesCover(names []string, domain string) bool {
+	for _, name := range names {
+		if strings.EqualFold(name, domain) {
+			return true
+		}
+		if strings.HasPrefix(name, "*.") && !strings.HasPrefix(domain, "*.") {
+			if i := strings.IndexByte(domain, '.'); i > 0 && strings.EqualFold(name[2:], domain[i+1:]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func validateCertificateChainPem(v interface{}, path cty.Path) diag.Diagnostics {
+	certs, err := parseCertificateChain(v.(string))
+	if err == nil {
+		err = checkCertificateChain(certs, time.Now())
+	}
+	if err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Invalid certificate chain",
+			Detail:        err.Error(),
+			AttributePath: path,
+		}}
+	}
+	return nil
+}
+
+func validatePrivateKeyPem(v interface{}, path cty.Path) diag.Diagnostics {
+	if _, err := parsePrivateKey(v.(string)); err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Invalid private key",
+			Detail:        err.Error(),
+			AttributePath: path,
+		}}
+	}
+	return nil
+}
+
+// parseCertExpireTime parses the CertExpireTime returned by the CDN and DCDN APIs,
+// which is either RFC 3339, a "2006-01-02 15:04:05" time in UTC+8 or a Unix
+// timestamp in milliseconds depending on the API.
+func parseCertExpireTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04:05", v, time.FixedZone("CST", 8*60*60)); err == nil {
+		return t, nil
+	}
+	if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.UnixMilli(ms), nil
+	}
+	return time.Time{}, fmt.Errorf("unrecognized certificate expire time %q", v)
+}
+
+// generatePrivateKey creates a private key of the given type, one of P256,
+// P384, RSA2048 or RSA4096.
+func generatePrivateKey(keyType string) (crypto.Signer, error) {
+	switch keyType {
+	case "P256":
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "P384":
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case "RSA2048":
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case "RSA4096":
+		return rsa.GenerateKey(rand.Reader, 4096)
+	}
+	return nil, fmt.Errorf("unsupported key type %q", keyType)
+}
+
+// encodePrivateKeyPem encodes key as PKCS #1 for RSA keys and SEC 1 for EC
+// keys, the formats accepted by DCDN.
+func encodePrivateKeyPem(key crypto.Signer) (string, error) {
+	var block *pem.Block
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		block = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	case *ecdsa.PrivateKey:
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return "", err
+		}
+		block = &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}
+	default:
+		return "", fmt.Errorf("unsupported private key type %T", key)
+	}
+	return string(pem.EncodeToMemory(block)), nil
+}
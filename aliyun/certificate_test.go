@@ -0,0 +1,169 @@
+package aliyun
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/hashicorp/go-cty/cty"
+	"math/big"
+	"testing"
+	"time"
+)
+
+type testCertificate struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPem string
+	keyPem  string
+}
+
+// newTestCertificate issues a certificate for names, signed by parent or
+// self-signed when parent is nil. A certificate without names is a CA.
+func newTestCertificate(t *testing.T, names []string, notBefore, notAfter time.Time, parent *testCertificate) *testCertificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "test ca"},
+		DNSNames:     names,
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	if len(names) == 0 {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		template.Subject.CommonName = names[0]
+	}
+
+	issuer, signer := template, key
+	if parent != nil {
+		issuer, signer = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, key.Public(), signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCertificate{
+		cert:    cert,
+		key:     key,
+		certPem: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPem:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})),
+	}
+}
+
+func TestCertificateNamesCover(t *testing.T) {
+	cases := []struct {
+		names  []string
+		domain string
+		want   bool
+	}{
+		{[]string{"example.com"}, "example.com", true},
+		{[]string{"Example.COM"}, "example.com", true},
+		{[]string{"example.com", "www.example.com"}, "www.example.com", true},
+		{[]string{"example.com"}, "www.example.com", false},
+		{[]string{"*.example.com"}, "www.example.com", true},
+		{[]string{"*.example.com"}, "a.www.example.com", false},
+		{[]string{"*.example.com"}, "example.com", false},
+		{[]string{"*.example.com"}, "*.example.com", true},
+		{[]string{"www.example.com"}, "*.example.com", false},
+		{[]string{"*.www.example.com"}, "*.example.com", false},
+		{nil, "example.com", false},
+	}
+
+	for _, c := range cases {
+		if got := certificateNamesCover(c.names, c.domain); got != c.want {
+			t.Errorf("certificateNamesCover(%v, %q) = %t, want %t", c.names, c.domain, got, c.want)
+		}
+	}
+}
+
+func TestCheckCertificateChain(t *testing.T) {
+	now := time.Now()
+	ca := newTestCertificate(t, nil, now.Add(-time.Hour), now.Add(48*time.Hour), nil)
+	leaf := newTestCertificate(t, []string{"example.com"}, now.Add(-time.Hour), now.Add(24*time.Hour), ca)
+	other := newTestCertificate(t, []string{"example.com"}, now.Add(-time.Hour), now.Add(24*time.Hour), nil)
+	expired := newTestCertificate(t, []string{"example.com"}, now.Add(-48*time.Hour), now.Add(-time.Hour), ca)
+	future := newTestCertificate(t, []string{"example.com"}, now.Add(time.Hour), now.Add(48*time.Hour), ca)
+
+	cases := []struct {
+		name    string
+		certs   []*x509.Certificate
+		wantErr bool
+	}{
+		{"leaf only", []*x509.Certificate{leaf.cert}, false},
+		{"leaf and issuer", []*x509.Certificate{leaf.cert, ca.cert}, false},
+		{"issuer first", []*x509.Certificate{ca.cert, leaf.cert}, true},
+		{"unrelated issuer", []*x509.Certificate{other.cert, ca.cert}, true},
+		{"expired", []*x509.Certificate{expired.cert, ca.cert}, true},
+		{"not yet valid", []*x509.Certificate{future.cert, ca.cert}, true},
+		{"empty", nil, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := checkCertificateChain(c.certs, now)
+			if (err != nil) != c.wantErr {
+				t.Errorf("checkCertificateChain() error = %v, want error %t", err, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckCertificateForDomain(t *testing.T) {
+	now := time.Now()
+	cert := newTestCertificate(t, []string{"example.com", "*.example.com"}, now.Add(-time.Hour), now.Add(24*time.Hour), nil)
+	other := newTestCertificate(t, []string{"example.com"}, now.Add(-time.Hour), now.Add(24*time.Hour), nil)
+
+	cases := []struct {
+		name     string
+		certPem  string
+		keyPem   string
+		domain   string
+		wantPath string
+	}{
+		{"matching", cert.certPem, cert.keyPem, "example.com", ""},
+		{"wildcard", cert.certPem, cert.keyPem, "www.example.com", ""},
+		{"key of another certificate", cert.certPem, other.keyPem, "example.com", "ssl_pri"},
+		{"domain not covered", cert.certPem, cert.keyPem, "example.org", "ssl_pub"},
+		{"domain below wildcard", cert.certPem, cert.keyPem, "a.www.example.com", "ssl_pub"},
+		{"no certificate", "", cert.keyPem, "example.com", ""},
+		{"no private key", cert.certPem, "", "example.com", ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			diags := checkCertificateForDomain(c.certPem, c.keyPem, c.domain)
+			if c.wantPath == "" {
+				if len(diags) != 0 {
+					t.Errorf("unexpected diagnostics: %v", diags)
+				}
+				return
+			}
+			if len(diags) != 1 || !diags.HasError() {
+				t.Fatalf("expected one error, got %v", diags)
+			}
+			if path := diags[0].AttributePath; len(path) != 1 || path[0].(cty.GetAttrStep).Name != c.wantPath {
+				t.Errorf("error points at %#v, want %s", path, c.wantPath)
+			}
+		})
+	}
+}
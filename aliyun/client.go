@@ -1,13 +1,20 @@
 package aliyun
 
 import (
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/alidns"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/cas"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/cdn"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/cr"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
 	"github.com/aliyun/fc-go-sdk"
 )
 
 type Client struct {
-	fcconn   *fc.Client
-	crconn   *cr.Client
-	dcdnconn *dcdn.Client
+	fcconn     *fc.Client
+	crconn     *cr.Client
+	dcdnconn   *dcdn.Client
+	alidnsconn *alidns.Client
+	casconn    *cas.Client
+	cdnconn    *cdn.Client
+	slsconn    *slsClient
 }
@@ -1,6 +1,8 @@
 package aliyun
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
 	"github.com/aliyun/fc-go-sdk"
@@ -82,6 +84,28 @@ func Int64Pointer(i int64) *int64 {
 	return &i
 }
 
+func sha256Hex(v interface{}) string {
+	hash := sha256.Sum256([]byte(v.(string)))
+	return hex.EncodeToString(hash[:])
+}
+
+func expandStringList(v []interface{}) []string {
+	s := make([]string, 0, len(v))
+	for _, vv := range v {
+		if vv != nil {
+			s = append(s, vv.(string))
+		}
+	}
+	return s
+}
+
+func OnOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
 func IsExpectedErrors(err error, expectCodes []string) bool {
 	if err == nil {
 		return false
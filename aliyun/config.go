@@ -3,6 +3,9 @@ package aliyun
 import (
 	"fmt"
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/alidns"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/cas"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/cdn"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/cr"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
 	"github.com/aliyun/fc-go-sdk"
@@ -32,11 +35,19 @@ func (c *Config) Client() Client {
 	fcconn, _ := c.newFcClient()
 	crconn, _ := c.newCrClient()
 	dcdnconn, _ := c.newDcdnClient()
+	alidnsconn, _ := c.newAlidnsClient()
+	casconn, _ := c.newCasClient()
+	cdnconn, _ := c.newCdnClient()
+	slsconn := c.newSlsClient()
 
 	client := Client{
-		fcconn:   fcconn,
-		crconn:   crconn,
-		dcdnconn: dcdnconn,
+		fcconn:     fcconn,
+		crconn:     crconn,
+		dcdnconn:   dcdnconn,
+		alidnsconn: alidnsconn,
+		casconn:    casconn,
+		cdnconn:    cdnconn,
+		slsconn:    slsconn,
 	}
 
 	return client
@@ -80,3 +91,28 @@ func (c *Config) newCrClient() (*cr.Client, error) {
 func (c *Config) newDcdnClient() (*dcdn.Client, error) {
 	return dcdn.NewClientWithAccessKey(c.RegionId, c.AccessKey, c.SecretKey)
 }
+
+func (c *Config) newCdnClient() (*cdn.Client, error) {
+	return cdn.NewClientWithAccessKey(c.RegionId, c.AccessKey, c.SecretKey)
+}
+
+func (c *Config) newSlsClient() *slsClient {
+	return &slsClient{
+		accessKey: c.AccessKey,
+		secretKey: c.SecretKey,
+		httpClient: &http.Client{
+			Timeout:   time.Duration(30) * time.Second,
+			Transport: c.getTransport(),
+		},
+	}
+}
+
+func (c *Config) newAlidnsClient() (*alidns.Client, error) {
+	return alidns.NewClientWithAccessKey(c.RegionId, c.AccessKey, c.SecretKey)
+}
+
+// Certificates uploaded to CAS live in cn-hangzhou whatever the provider
+// region, which is also where DCDN looks them up by default.
+func (c *Config) newCasClient() (*cas.Client, error) {
+	return cas.NewClientWithAccessKey(string(Hangzhou), c.AccessKey, c.SecretKey)
+}
@@ -0,0 +1,140 @@
+package aliyun
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"strings"
+	"time"
+)
+
+func dataSourceAliyunDcdnDomainLogs() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAliyunDcdnDomainLogsRead,
+
+		Schema: map[string]*schema.Schema{
+			"domain_name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"start_time": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"end_time": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"names": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"logs": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"size": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"start_time": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"end_time": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"url": {
+							Type:      schema.TypeString,
+							Computed:  true,
+							Sensitive: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAliyunDcdnDomainLogsRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	startTime, _ := time.Parse(time.RFC3339, d.Get("start_time").(string))
+	endTime, _ := time.Parse(time.RFC3339, d.Get("end_time").(string))
+	if !endTime.After(startTime) {
+		return diag.Errorf("end_time must be later than start_time")
+	}
+
+	request := dcdn.CreateDescribeDcdnDomainLogRequest()
+	request.DomainName = d.Get("domain_name").(string)
+	request.StartTime = startTime.UTC().Format("2006-01-02T15:04:05Z")
+	request.EndTime = endTime.UTC().Format("2006-01-02T15:04:05Z")
+	request.PageSize = requests.NewInteger(1000)
+
+	names := make([]string, 0)
+	logs := make([]map[string]interface{}, 0)
+	for page, seen := 1, 0; ; page++ {
+		request.PageNumber = requests.NewInteger(page)
+
+		res, err := conn.DescribeDcdnDomainLog(request)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		var total int64
+		count := 0
+		for _, detail := range res.DomainLogDetails.DomainLogDetail {
+			total += detail.PageInfos.Total
+			for _, info := range detail.LogInfos.LogInfoDetail {
+				names = append(names, info.LogName)
+				logs = append(logs, map[string]interface{}{
+					"name":       info.LogName,
+					"size":       int(info.LogSize),
+					"start_time": info.StartTime,
+					"end_time":   info.EndTime,
+					"url":        dcdnDomainLogUrl(info.LogPath),
+				})
+				count++
+			}
+		}
+
+		seen += count
+		if count == 0 || int64(seen) >= total {
+			break
+		}
+	}
+
+	hash := sha256.Sum256([]byte(strings.Join(names, ",")))
+	d.SetId(hex.EncodeToString(hash[:]))
+	if err := d.Set("names", names); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("logs", logs); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+// LogPath is returned without a scheme, e.g. "cdnlog.oss-cn-hangzhou.aliyuncs.com/...?Expires=...".
+func dcdnDomainLogUrl(path string) string {
+	if path == "" || strings.Contains(path, "://") {
+		return path
+	}
+	return "https://" + path
+}
@@ -0,0 +1,201 @@
+package aliyun
+
+import (
+	"context"
+	"fmt"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func dataSourceAliyunDcdnDomainStats() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAliyunDcdnDomainStatsRead,
+
+		Schema: map[string]*schema.Schema{
+			"domain_name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"start_time": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"end_time": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"interval": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      300,
+				ValidateFunc: validation.IntInSlice([]int{300, 3600, 86400}),
+			},
+			"bps": dcdnDomainStatsSeriesSchema(),
+			"bps_peak": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"bps_p95": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"traffic": dcdnDomainStatsSeriesSchema(),
+			"traffic_peak": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"traffic_total": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"hit_rate": dcdnDomainStatsSeriesSchema(),
+			"hit_rate_average": {
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dcdnDomainStatsSeriesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"time_stamp": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"value": {
+					Type:     schema.TypeFloat,
+					Computed: true,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAliyunDcdnDomainStatsRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	domain := d.Get("domain_name").(string)
+	startTime, _ := time.Parse(time.RFC3339, d.Get("start_time").(string))
+	endTime, _ := time.Parse(time.RFC3339, d.Get("end_time").(string))
+	if !endTime.After(startTime) {
+		return diag.Errorf("end_time must be later than start_time")
+	}
+	start := startTime.UTC().Format("2006-01-02T15:04:05Z")
+	end := endTime.UTC().Format("2006-01-02T15:04:05Z")
+	interval := strconv.Itoa(d.Get("interval").(int))
+
+	bpsRequest := dcdn.CreateDescribeDcdnDomainBpsDataRequest()
+	bpsRequest.DomainName = domain
+	bpsRequest.StartTime = start
+	bpsRequest.EndTime = end
+	bpsRequest.Interval = interval
+
+	bpsRes, err := conn.DescribeDcdnDomainBpsData(bpsRequest)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	trafficRequest := dcdn.CreateDescribeDcdnDomainTrafficDataRequest()
+	trafficRequest.DomainName = domain
+	trafficRequest.StartTime = start
+	trafficRequest.EndTime = end
+	trafficRequest.Interval = interval
+
+	trafficRes, err := conn.DescribeDcdnDomainTrafficData(trafficRequest)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	hitRateRequest := dcdn.CreateDescribeDcdnDomainHitRateDataRequest()
+	hitRateRequest.DomainName = domain
+	hitRateRequest.StartTime = start
+	hitRateRequest.EndTime = end
+	hitRateRequest.Interval = interval
+
+	hitRateRes, err := conn.DescribeDcdnDomainHitRateData(hitRateRequest)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	bps, bpsValues := flattenDcdnDomainStatsSeries(bpsRes.BpsDataPerInterval.DataModule, func(v dcdn.DataModule) float64 { return v.Bps })
+	traffic, trafficValues := flattenDcdnDomainStatsSeries(trafficRes.TrafficDataPerInterval.DataModule, func(v dcdn.DataModule) float64 { return v.Traffic })
+	hitRate, hitRateValues := flattenDcdnDomainStatsSeries(hitRateRes.HitRatePerInterval.DataModule, func(v dcdn.DataModule) float64 {
+		if rate, err := strconv.ParseFloat(strings.TrimSuffix(v.Value, "%"), 64); err == nil {
+			return rate
+		}
+		return v.ByteHitRate
+	})
+
+	d.SetId(fmt.Sprintf("%s:%s:%s:%s", domain, start, end, interval))
+	if err := d.Set("bps", bps); err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("bps_peak", percentile(bpsValues, 100))
+	d.Set("bps_p95", percentile(bpsValues, 95))
+	if err := d.Set("traffic", traffic); err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("traffic_peak", percentile(trafficValues, 100))
+	d.Set("traffic_total", sum(trafficValues))
+	if err := d.Set("hit_rate", hitRate); err != nil {
+		return diag.FromErr(err)
+	}
+	if len(hitRateValues) > 0 {
+		d.Set("hit_rate_average", sum(hitRateValues)/float64(len(hitRateValues)))
+	} else {
+		d.Set("hit_rate_average", 0)
+	}
+
+	return diags
+}
+
+func flattenDcdnDomainStatsSeries(data []dcdn.DataModule, value func(dcdn.DataModule) float64) ([]map[string]interface{}, []float64) {
+	series := make([]map[string]interface{}, 0, len(data))
+	values := make([]float64, 0, len(data))
+	for _, v := range data {
+		series = append(series, map[string]interface{}{
+			"time_stamp": v.TimeStamp,
+			"value":      value(v),
+		})
+		values = append(values, value(v))
+	}
+	return series, values
+}
+
+// percentile returns the p-th percentile of values using the nearest-rank
+// method, so percentile(values, 100) is the peak.
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func sum(values []float64) float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
@@ -0,0 +1,192 @@
+package aliyun
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var dcdnDomainScopes = []string{"domestic", "overseas", "global"}
+
+func dataSourceAliyunDcdnDomains() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAliyunDcdnDomainsRead,
+
+		Schema: map[string]*schema.Schema{
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"status": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"online", "offline", "configuring", "configure_failed", "checking", "check_failed", "stopping", "deleting"}, false),
+			},
+			"scope": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(dcdnDomainScopes, false),
+			},
+			"resource_group_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tags": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"domains": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"domain_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"cname": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"scope": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"resource_group_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"sources": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"content": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"port": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+									"priority": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"type": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"weight": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// DescribeDcdnUserDomains does not report the scope of a domain, so each
+// scope is queried separately through the Coverage filter.
+func dataSourceAliyunDcdnDomainsRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+
+	scopes := dcdnDomainScopes
+	if v, ok := d.GetOk("scope"); ok {
+		scopes = []string{v.(string)}
+	}
+
+	var tags []dcdn.DescribeDcdnUserDomainsTag
+	for k, v := range d.Get("tags").(map[string]interface{}) {
+		tags = append(tags, dcdn.DescribeDcdnUserDomainsTag{Key: k, Value: v.(string)})
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Key < tags[j].Key })
+
+	ids := make([]string, 0)
+	domains := make([]map[string]interface{}, 0)
+	for _, scope := range scopes {
+		request := dcdn.CreateDescribeDcdnUserDomainsRequest()
+		request.Coverage = scope
+		request.DomainStatus = d.Get("status").(string)
+		request.ResourceGroupId = d.Get("resource_group_id").(string)
+		if len(tags) > 0 {
+			request.Tag = &tags
+		}
+		request.PageSize = requests.NewInteger(50)
+
+		for page, seen := 1, 0; ; page++ {
+			request.PageNumber = requests.NewInteger(page)
+
+			res, err := conn.DescribeDcdnUserDomains(request)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			for _, domain := range res.Domains.PageData {
+				if nameRegex != nil && !nameRegex.MatchString(domain.DomainName) {
+					continue
+				}
+				ids = append(ids, domain.DomainName)
+				domains = append(domains, map[string]interface{}{
+					"domain_name":       domain.DomainName,
+					"cname":             domain.Cname,
+					"status":            domain.DomainStatus,
+					"scope":             scope,
+					"resource_group_id": domain.ResourceGroupId,
+					"description":       domain.Description,
+					"sources":           flattenDcdnSources(domain.Sources.Source),
+				})
+			}
+
+			seen += len(res.Domains.PageData)
+			if len(res.Domains.PageData) == 0 || int64(seen) >= res.TotalCount {
+				break
+			}
+		}
+	}
+
+	hash := sha256.Sum256([]byte(strings.Join(ids, ",")))
+	d.SetId(hex.EncodeToString(hash[:]))
+	if err := d.Set("ids", ids); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("domains", domains); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
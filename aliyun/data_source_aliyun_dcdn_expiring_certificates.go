@@ -0,0 +1,105 @@
+package aliyun
+
+import (
+	"context"
+	"fmt"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"time"
+)
+
+func dataSourceAliyunDcdnExpiringCertificates() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAliyunDcdnExpiringCertificatesRead,
+
+		Schema: map[string]*schema.Schema{
+			"days": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"certificates": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"domain_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"cert_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"cert_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"issuer": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"not_after": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"days_left": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAliyunDcdnExpiringCertificatesRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	days := d.Get("days").(int)
+	deadline := time.Now().Add(time.Duration(days) * 24 * time.Hour)
+
+	certificates := make([]map[string]interface{}, 0)
+	request := dcdn.CreateDescribeDcdnHttpsDomainListRequest()
+	request.PageSize = requests.NewInteger(50)
+	for page, seen := 1, 0; ; page++ {
+		request.PageNumber = requests.NewInteger(page)
+
+		res, err := conn.DescribeDcdnHttpsDomainList(request)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		for _, certInfo := range res.CertInfos.CertInfo {
+			notAfter, err := parseCertExpireTime(certInfo.CertExpireTime)
+			if err != nil || notAfter.After(deadline) {
+				continue
+			}
+			certificates = append(certificates, map[string]interface{}{
+				"domain_name": certInfo.DomainName,
+				"cert_name":   certInfo.CertName,
+				"cert_type":   certInfo.CertType,
+				"issuer":      certInfo.Issuer,
+				"not_after":   notAfter.UTC().Format(time.RFC3339),
+				"days_left":   int(time.Until(notAfter).Hours() / 24),
+			})
+		}
+
+		seen += len(res.CertInfos.CertInfo)
+		if len(res.CertInfos.CertInfo) == 0 || seen >= res.TotalCount {
+			break
+		}
+	}
+
+	d.SetId(fmt.Sprintf("%d", days))
+	if err := d.Set("certificates", certificates); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
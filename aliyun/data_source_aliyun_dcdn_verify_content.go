@@ -0,0 +1,55 @@
+package aliyun
+
+import (
+	"context"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceAliyunDcdnVerifyContent() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAliyunDcdnVerifyContentRead,
+
+		Schema: map[string]*schema.Schema{
+			"domain_name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"content": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"record_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"record_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceAliyunDcdnVerifyContentRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	domain := d.Get("domain_name").(string)
+
+	request := dcdn.CreateDescribeDcdnVerifyContentRequest()
+	request.DomainName = domain
+
+	res, err := conn.DescribeDcdnVerifyContent(request)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(domain)
+	d.Set("content", res.Content)
+	d.Set("record_name", "verification")
+	d.Set("record_type", "TXT")
+
+	return diags
+}
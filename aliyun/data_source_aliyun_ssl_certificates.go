@@ -0,0 +1,157 @@
+package aliyun
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func dataSourceAliyunSslCertificates() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAliyunSslCertificatesRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"name_regex"},
+			},
+			"name_regex": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"name"},
+				ValidateFunc:  validation.StringIsValidRegExp,
+			},
+			"domain": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"include_expired": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"certificates": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cert_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"common_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"subject_alt_names": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"issuer": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"fingerprint": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"not_after": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"expired": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAliyunSslCertificatesRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).casconn
+
+	name := d.Get("name").(string)
+	domain := d.Get("domain").(string)
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+
+	keyword := name
+	if keyword == "" && domain != "" && !strings.HasPrefix(domain, "*.") {
+		keyword = domain
+	}
+
+	all, err := listCasCertificates(conn, keyword)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	ids := make([]string, 0)
+	certificates := make([]map[string]interface{}, 0)
+	for _, certificate := range all {
+		if name != "" && certificate.Name != name {
+			continue
+		}
+		if nameRegex != nil && !nameRegex.MatchString(certificate.Name) {
+			continue
+		}
+		if certificate.Expired && !d.Get("include_expired").(bool) {
+			continue
+		}
+
+		names := []string{certificate.CommonName}
+		if certificate.Sans != "" {
+			names = append(names, strings.Split(certificate.Sans, ",")...)
+		}
+		if domain != "" && !certificateNamesCover(names, domain) {
+			continue
+		}
+
+		certId := strconv.FormatInt(certificate.CertificateId, 10)
+		ids = append(ids, certId)
+		certificates = append(certificates, map[string]interface{}{
+			"cert_id":           certId,
+			"name":              certificate.Name,
+			"common_name":       certificate.CommonName,
+			"subject_alt_names": names[1:],
+			"issuer":            certificate.Issuer,
+			"fingerprint":       certificate.Fingerprint,
+			"not_after":         time.UnixMilli(certificate.CertEndTime).UTC().Format(time.RFC3339),
+			"expired":           certificate.Expired,
+		})
+	}
+
+	hash := sha256.Sum256([]byte(strings.Join(ids, ",")))
+	d.SetId(hex.EncodeToString(hash[:]))
+	if err := d.Set("ids", ids); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("certificates", certificates); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
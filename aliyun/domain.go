@@ -0,0 +1,599 @@
+package aliyun
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Helpers shared by the CDN and DCDN domain resources, whose APIs only differ
+// in naming.
+
+// Domain statuses reported by DescribeCdnDomainDetail and
+// DescribeDcdnDomainDetail that are still in transition; anything outside
+// these and the target is treated as a failure.
+var (
+	domainPendingStatus       = []string{"checking", "configuring"}
+	domainStartPendingStatus  = []string{"offline", "checking", "configuring"}
+	domainStopPendingStatus   = []string{"online", "configuring", "stopping"}
+	domainDeletePendingStatus = []string{"online", "offline", "checking", "configuring", "stopping", "deleting"}
+)
+
+func domainTargetStatus(enabled bool) string {
+	if enabled {
+		return "online"
+	}
+	return "offline"
+}
+
+func waitForDomainStatus(ctx context.Context, product, domain string, refresh resource.StateRefreshFunc, pending, target []string, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending:    pending,
+		Target:     target,
+		Refresh:    refresh,
+		Timeout:    timeout,
+		MinTimeout: 3 * time.Second,
+	}
+
+	_, err := stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		if e, ok := err.(*resource.UnexpectedStateError); ok {
+			return fmt.Errorf("error waiting for %s domain %s: current status is %s", product, domain, e.State)
+		}
+		if e, ok := err.(*resource.TimeoutError); ok {
+			return fmt.Errorf("timeout waiting for %s domain %s: current status is %s", product, domain, e.LastState)
+		}
+		return fmt.Errorf("error waiting for %s domain %s: %s", product, domain, err)
+	}
+
+	return nil
+}
+
+// domainConfig is a config of a CDN or DCDN domain, as returned by
+// DescribeCdnDomainConfigs, DescribeDcdnDomainConfigs and
+// DescribeDcdnIpaDomainConfigs.
+type domainConfig struct {
+	ConfigId     string
+	FunctionName string
+	Status       string
+	Args         map[string]string
+}
+
+// domainConfigsFunc lists the configs of functionNames on a domain through
+// the API of one product.
+type domainConfigsFunc func(domain string, functionNames []string) ([]domainConfig, error)
+
+// describeDomainConfigs returns the configs of functionNames ordered by
+// config id, which follows the order the rules were added in.
+func describeDomainConfigs(domain string, functionNames []string, describe domainConfigsFunc) ([]domainConfig, error) {
+	configs, err := describe(domain, functionNames)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(configs, func(i, j int) bool {
+		a, _ := strconv.Atoi(configs[i].ConfigId)
+		b, _ := strconv.Atoi(configs[j].ConfigId)
+		return a < b
+	})
+
+	return configs, nil
+}
+
+// parseDomainConfigId returns the domain, function name and config id a
+// domain config resource is keyed by. Ids from before the config id was part
+// of them fall back to the config_id attribute, which may be empty.
+func parseDomainConfigId(d *schema.ResourceData) (domain, functionName, configId string, err error) {
+	if parts := strings.Split(d.Id(), COLON_SEPARATED); len(parts) == 2 {
+		return parts[0], parts[1], d.Get("config_id").(string), nil
+	}
+
+	parts, err := ParseResourceId(d.Id(), 3)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return parts[0], parts[1], parts[2], nil
+}
+
+// findDomainConfig returns the config with configId. Without a config id, it
+// returns the only config listed, if there is exactly one.
+func findDomainConfig(configs []domainConfig, configId string) (domainConfig, bool) {
+	if configId == "" {
+		if len(configs) == 1 {
+			return configs[0], true
+		}
+		return domainConfig{}, false
+	}
+
+	for _, config := range configs {
+		if config.ConfigId == configId {
+			return config, true
+		}
+	}
+
+	return domainConfig{}, false
+}
+
+// filterDomainConfigs returns the configs whose id is in configIds, in the
+// order of configs.
+func filterDomainConfigs(configs []domainConfig, configIds []string) []domainConfig {
+	ids := make(map[string]bool, len(configIds))
+	for _, configId := range configIds {
+		ids[configId] = true
+	}
+
+	filtered := make([]domainConfig, 0, len(configIds))
+	for _, config := range configs {
+		if ids[config.ConfigId] {
+			filtered = append(filtered, config)
+		}
+	}
+
+	return filtered
+}
+
+// setDomainFunctions applies functions with batchSet, which takes the
+// Functions payload of the product's batch config API, and returns the config
+// id of each function in order once the configs are listed. The configs may
+// still be deploying: callers record the ids before waiting for them with
+// waitForDomainConfigs, so that a failed wait does not lose track of them.
+func setDomainFunctions(ctx context.Context, product, domain string, functions []map[string]interface{}, batchSet func(functions string) error, describe domainConfigsFunc, timeout time.Duration) ([]string, error) {
+	functionNames := domainFunctionNames(functions)
+
+	// The batch config APIs do not return the ids of added rules, so they are
+	// told apart from the configs that already existed.
+	configs, err := describe(domain, functionNames)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(configs))
+	for _, config := range configs {
+		existing[config.ConfigId] = true
+	}
+
+	payload, err := json.Marshal(functions)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := batchSet(string(payload)); err != nil {
+		return nil, err
+	}
+
+	var configIds []string
+	err = resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		configs, err := describeDomainConfigs(domain, functionNames, describe)
+		if err != nil {
+			return resource.NonRetryableError(fmt.Errorf("error describing %s configs %s of %s: %s", product, strings.Join(functionNames, ","), domain, err))
+		}
+
+		configIds = matchDomainConfigs(functions, configs, existing)
+		for i, configId := range configIds {
+			if configId == "" {
+				return resource.RetryableError(fmt.Errorf("%s config %s of %s is not found yet", product, functions[i]["functionName"], domain))
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return configIds, nil
+}
+
+// waitForDomainConfigs waits for the configs in configIds, written for
+// functions, to be deployed.
+func waitForDomainConfigs(ctx context.Context, product, domain string, functions []map[string]interface{}, configIds []string, describe domainConfigsFunc, timeout time.Duration) error {
+	functionNames := domainFunctionNames(functions)
+
+	return resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		configs, err := describe(domain, functionNames)
+		if err != nil {
+			return resource.NonRetryableError(fmt.Errorf("error describing %s configs %s of %s: %s", product, strings.Join(functionNames, ","), domain, err))
+		}
+
+		byId := make(map[string]domainConfig, len(configs))
+		for _, config := range configs {
+			byId[config.ConfigId] = config
+		}
+
+		for _, configId := range configIds {
+			config, ok := byId[configId]
+			if !ok {
+				return resource.NonRetryableError(fmt.Errorf("%s config %s of %s is not found", product, configId, domain))
+			}
+
+			switch config.Status {
+			case "success":
+			case "failed":
+				return resource.NonRetryableError(fmt.Errorf("%s config %s (%s) of %s failed to apply", product, config.FunctionName, config.ConfigId, domain))
+			default:
+				return resource.RetryableError(fmt.Errorf("%s config %s (%s) of %s is %s", product, config.FunctionName, config.ConfigId, domain, config.Status))
+			}
+		}
+
+		return nil
+	})
+}
+
+// matchDomainConfigs returns the config id of each function: its configId
+// when it updates a rule, otherwise the config added for it, looked up among
+// the configs not in existing and preferring those with the same arguments.
+// Functions whose config is not listed yet get an empty id.
+func matchDomainConfigs(functions []map[string]interface{}, configs []domainConfig, existing map[string]bool) []string {
+	configIds := make([]string, len(functions))
+	claimed := make(map[string]bool, len(functions))
+	for i, function := range functions {
+		if id, ok := function["configId"]; ok {
+			configIds[i] = strconv.Itoa(id.(int))
+			claimed[configIds[i]] = true
+		}
+	}
+
+	for _, sameArgs := range []bool{true, false} {
+		for i, function := range functions {
+			if configIds[i] != "" {
+				continue
+			}
+			for _, config := range configs {
+				if existing[config.ConfigId] || claimed[config.ConfigId] || config.FunctionName != function["functionName"].(string) {
+					continue
+				}
+				if sameArgs && !domainConfigHasArgs(config, function) {
+					continue
+				}
+				configIds[i] = config.ConfigId
+				claimed[config.ConfigId] = true
+				break
+			}
+		}
+	}
+
+	return configIds
+}
+
+func domainConfigHasArgs(config domainConfig, function map[string]interface{}) bool {
+	for _, arg := range function["functionArgs"].([]map[string]interface{}) {
+		if config.Args[arg["argName"].(string)] != arg["argValue"].(string) {
+			return false
+		}
+	}
+	return true
+}
+
+func domainFunctionNames(functions []map[string]interface{}) []string {
+	var functionNames []string
+	seen := make(map[string]bool, len(functions))
+	for _, function := range functions {
+		functionName := function["functionName"].(string)
+		if !seen[functionName] {
+			seen[functionName] = true
+			functionNames = append(functionNames, functionName)
+		}
+	}
+	return functionNames
+}
+
+// domainFunction builds one entry of the Functions payload accepted by
+// BatchSetCdnDomainConfig and BatchSetDcdnDomainConfigs. An empty configId
+// adds a new rule.
+func domainFunction(functionName, configId string, args map[string]string) (map[string]interface{}, error) {
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	functionArgs := make([]map[string]interface{}, len(names))
+	for i, name := range names {
+		functionArgs[i] = map[string]interface{}{
+			"argName":  name,
+			"argValue": args[name],
+		}
+	}
+
+	function := map[string]interface{}{
+		"functionArgs": functionArgs,
+		"functionName": functionName,
+	}
+	if configId != "" {
+		id, err := strconv.Atoi(configId)
+		if err != nil {
+			return nil, err
+		}
+		function["configId"] = id
+	}
+
+	return function, nil
+}
+
+// Origin priorities as named in configuration and as sent to the APIs.
+var domainSourcePriorities = map[string]string{
+	"primary": "20",
+	"backup":  "30",
+}
+
+func domainSourcesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Required: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"content": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"port": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      80,
+					ValidateFunc: validation.IsPortNumber,
+				},
+				"priority": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "primary",
+					ValidateFunc: validation.StringInSlice([]string{"primary", "backup"}, false),
+				},
+				"type": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.StringInSlice([]string{"ipaddr", "domain", "oss", "fc_domain"}, false),
+				},
+				"weight": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      10,
+					ValidateFunc: validation.IntBetween(1, 100),
+				},
+			},
+		},
+	}
+}
+
+// customizeDomainSourcesDiff rejects sources without a primary origin, which
+// the APIs only refuse once the domain is being configured.
+func customizeDomainSourcesDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("sources") {
+		return nil
+	}
+
+	for _, v := range d.Get("sources").(*schema.Set).List() {
+		if v.(map[string]interface{})["priority"].(string) == "primary" {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("at least one of sources must have priority primary")
+}
+
+// domainSource is an origin as returned by DescribeCdnDomainDetail and
+// DescribeDcdnDomainDetail.
+type domainSource struct {
+	Content  string
+	Port     int
+	Priority string
+	Type     string
+	Weight   string
+}
+
+func flattenDomainSources(v []domainSource) []map[string]interface{} {
+	sources := make([]map[string]interface{}, 0, len(v))
+	for _, val := range v {
+		weight, _ := strconv.Atoi(val.Weight)
+		sources = append(sources, map[string]interface{}{
+			"content":  val.Content,
+			"port":     val.Port,
+			"priority": domainSourcePriority(val.Priority),
+			"type":     val.Type,
+			"weight":   weight,
+		})
+	}
+	return sources
+}
+
+// domainSourcePriority returns the name of a priority reported by the APIs,
+// or the priority itself when it has none.
+func domainSourcePriority(v string) string {
+	for name, priority := range domainSourcePriorities {
+		if priority == v {
+			return name
+		}
+	}
+	return v
+}
+
+func convertSourcesToString(v []interface{}) (string, error) {
+	arrayMaps := make([]interface{}, len(v))
+	for i, vv := range v {
+		item := vv.(map[string]interface{})
+		arrayMaps[i] = map[string]interface{}{
+			"Content":  item["content"],
+			"Port":     item["port"],
+			"Priority": domainSourcePriorities[item["priority"].(string)],
+			"Type":     item["type"],
+			"Weight":   strconv.Itoa(item["weight"].(int)),
+		}
+	}
+	maps, err := json.Marshal(arrayMaps)
+	if err != nil {
+		return "", err
+	}
+	return string(maps), nil
+}
+
+// customizeDomainCertDiff validates an uploaded certificate against its
+// private key and domain, and replaces the certificate once it is due for
+// renewal. It serves the CDN and DCDN certificate resources, which share
+// these arguments.
+func customizeDomainCertDiff(d *schema.ResourceDiff) error {
+	// An uploaded certificate is only replaced when ssl_pub holds a newer
+	// one: uploading the same certificate again would leave it due for
+	// renewal, and the resource replaced on every apply.
+	if d.Get("ready_for_renewal").(bool) && !domainCertUploadDueForRenewal(d) {
+		if err := d.SetNew("ready_for_renewal", false); err != nil {
+			return err
+		}
+		if err := d.ForceNew("ready_for_renewal"); err != nil {
+			return err
+		}
+	}
+
+	if d.GetRawConfig().IsNull() || d.Get("cert_type").(string) != "upload" {
+		return nil
+	}
+
+	if d.Get("cert_name").(string) == "" && d.NewValueKnown("cert_name") {
+		return fmt.Errorf("cert_name is required when cert_type is upload")
+	}
+	key, known, err := domainCertPrivateKey(d)
+	if err != nil {
+		return err
+	}
+	if !known {
+		return d.SetNewComputed("ssl_pri_sha256")
+	}
+	if d.NewValueKnown("ssl_pub") && d.Get("ssl_pub").(string) == "" || key == "" {
+		return fmt.Errorf("ssl_pub and one of ssl_pri, ssl_pri_file or ssl_pri_env are required when cert_type is upload")
+	}
+	if _, err := parsePrivateKey(key); err != nil {
+		return fmt.Errorf("invalid private key: %s", err)
+	}
+	if d.NewValueKnown("ssl_pub") && d.NewValueKnown("domain_name") {
+		// CustomizeDiff cannot return diagnostics, so the attribute is only
+		// named in the message here; checkDomainCertificate reports it with
+		// its path at apply time.
+		if diags := checkCertificateForDomain(d.Get("ssl_pub").(string), key, d.Get("domain_name").(string)); diags.HasError() {
+			return fmt.Errorf("%s", diags[0].Detail)
+		}
+	}
+	if hash := sha256Hex(key); d.Get("ssl_pri_sha256").(string) != hash {
+		return d.SetNew("ssl_pri_sha256", hash)
+	}
+
+	return nil
+}
+
+// domainCertUploadDueForRenewal reports whether the certificate is uploaded
+// and ssl_pub itself expires within renew_before_days, or is not yet known.
+func domainCertUploadDueForRenewal(d *schema.ResourceDiff) bool {
+	if d.Get("cert_type").(string) != "upload" {
+		return false
+	}
+	if !d.NewValueKnown("ssl_pub") {
+		return true
+	}
+
+	cert, err := parseCertificatePem(d.Get("ssl_pub").(string))
+	if err != nil || cert == nil {
+		return true
+	}
+	renewBefore := time.Duration(d.Get("renew_before_days").(int)) * 24 * time.Hour
+
+	return time.Until(cert.NotAfter) < renewBefore
+}
+
+// checkDomainCertificate checks an uploaded certificate against its private
+// key and domain, which may only become known at apply time.
+func checkDomainCertificate(d *schema.ResourceData) diag.Diagnostics {
+	if d.Get("cert_type").(string) != "upload" {
+		return nil
+	}
+
+	key, _, err := domainCertPrivateKey(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return checkCertificateForDomain(d.Get("ssl_pub").(string), key, d.Get("domain_name").(string))
+}
+
+// domainCertPrivateKey resolves the private key from ssl_pri, ssl_pri_file or
+// ssl_pri_env. It reads the raw configuration because ssl_pri is only kept as
+// a hash in state; known is false while the configuration is not yet known.
+func domainCertPrivateKey(d interface{ GetRawConfig() cty.Value }) (key string, known bool, err error) {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsWhollyKnown() {
+		return "", false, nil
+	}
+
+	if v := config.GetAttr("ssl_pri"); !v.IsNull() {
+		return v.AsString(), true, nil
+	}
+	if v := config.GetAttr("ssl_pri_file"); !v.IsNull() {
+		data, err := os.ReadFile(v.AsString())
+		if err != nil {
+			return "", true, fmt.Errorf("error reading ssl_pri_file: %s", err)
+		}
+		return string(data), true, nil
+	}
+	if v := config.GetAttr("ssl_pri_env"); !v.IsNull() {
+		key := os.Getenv(v.AsString())
+		if key == "" {
+			return "", true, fmt.Errorf("environment variable %s referenced by ssl_pri_env is empty or not set", v.AsString())
+		}
+		return key, true, nil
+	}
+
+	return "", true, nil
+}
+
+// setDomainCertificateDetails sets the attributes derived from the deployed
+// certificate, warning when it falls within renew_before_days.
+func setDomainCertificateDetails(d *schema.ResourceData, product, issuer, certPem, expireTime string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	d.Set("issuer", issuer)
+
+	notAfter, _ := parseCertExpireTime(expireTime)
+	cert, err := parseCertificatePem(certPem)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if cert != nil {
+		notAfter = cert.NotAfter
+		fingerprint := sha256.Sum256(cert.Raw)
+		d.Set("fingerprint", hex.EncodeToString(fingerprint[:]))
+		if issuer == "" {
+			d.Set("issuer", cert.Issuer.String())
+		}
+		if err := d.Set("subject_alt_names", cert.DNSNames); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	readyForRenewal := false
+	if notAfter.IsZero() {
+		d.Set("not_after", expireTime)
+	} else {
+		d.Set("not_after", notAfter.UTC().Format(time.RFC3339))
+
+		renewBefore := time.Duration(d.Get("renew_before_days").(int)) * 24 * time.Hour
+		if renewBefore > 0 && time.Until(notAfter) < renewBefore {
+			readyForRenewal = true
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("%s certificate is due for renewal", product),
+				Detail:   fmt.Sprintf("The certificate of %s expires on %s, within renew_before_days (%d). The certificate will be replaced, once ssl_pub holds a renewed one if it is uploaded.", d.Id(), notAfter.UTC().Format(time.RFC3339), d.Get("renew_before_days").(int)),
+			})
+		}
+	}
+	d.Set("ready_for_renewal", readyForRenewal)
+
+	return diags
+}
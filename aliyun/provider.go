@@ -33,13 +33,42 @@ func Provider() *schema.Provider {
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"aliyun_fc_version":         resourceAliyunFCVersion(),
-			"aliyun_fc_trigger":         resourceAliyunFCTrigger(),
-			"aliyun_cr_user_info":       resourceAliyunCRUserInfo(),
-			"aliyun_cr_user_info_auth":  resourceAliyunCRUserInfoAuth(),
-			"aliyun_dcdn_domain":        resourceAliyunDcdnDomain(),
-			"aliyun_dcdn_domain_cert":   resourceAliyunDcdnDomainCert(),
-			"aliyun_dcdn_domain_config": resourceAliyunDcdnDomainConfig(),
+			"aliyun_fc_version":                 resourceAliyunFCVersion(),
+			"aliyun_fc_trigger":                 resourceAliyunFCTrigger(),
+			"aliyun_cr_user_info":               resourceAliyunCRUserInfo(),
+			"aliyun_cr_user_info_auth":          resourceAliyunCRUserInfoAuth(),
+			"aliyun_dcdn_domain":                resourceAliyunDcdnDomain(),
+			"aliyun_dcdn_domain_cert":           resourceAliyunDcdnDomainCert(),
+			"aliyun_dcdn_domain_config":         resourceAliyunDcdnDomainConfig(),
+			"aliyun_dcdn_domain_verification":   resourceAliyunDcdnDomainVerification(),
+			"aliyun_dcdn_domain_https":          resourceAliyunDcdnDomainHttps(),
+			"aliyun_dcdn_cache_rule":            resourceAliyunDcdnCacheRule(),
+			"aliyun_dcdn_access_control":        resourceAliyunDcdnAccessControl(),
+			"aliyun_dcdn_origin_settings":       resourceAliyunDcdnOriginSettings(),
+			"aliyun_dcdn_rewrite_rule":          resourceAliyunDcdnRewriteRule(),
+			"aliyun_dcdn_cache_purge":           resourceAliyunDcdnCachePurge(),
+			"aliyun_dcdn_er":                    resourceAliyunDcdnEr(),
+			"aliyun_dcdn_er_code":               resourceAliyunDcdnErCode(),
+			"aliyun_dcdn_er_route":              resourceAliyunDcdnErRoute(),
+			"aliyun_dcdn_waf_policy":            resourceAliyunDcdnWafPolicy(),
+			"aliyun_dcdn_waf_domain":            resourceAliyunDcdnWafDomain(),
+			"aliyun_dcdn_waf_rule":              resourceAliyunDcdnWafRule(),
+			"aliyun_dcdn_realtime_log_delivery": resourceAliyunDcdnRealtimeLogDelivery(),
+			"aliyun_cdn_domain":                 resourceAliyunCdnDomain(),
+			"aliyun_cdn_domain_config":          resourceAliyunCdnDomainConfig(),
+			"aliyun_cdn_domain_cert":            resourceAliyunCdnDomainCert(),
+			"aliyun_dcdn_ipa_domain":            resourceAliyunDcdnIpaDomain(),
+			"aliyun_dcdn_ipa_domain_config":     resourceAliyunDcdnIpaDomainConfig(),
+			"aliyun_ssl_certificate":            resourceAliyunSslCertificate(),
+			"aliyun_acme_certificate":           resourceAliyunAcmeCertificate(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"aliyun_dcdn_verify_content":        dataSourceAliyunDcdnVerifyContent(),
+			"aliyun_dcdn_expiring_certificates": dataSourceAliyunDcdnExpiringCertificates(),
+			"aliyun_dcdn_domains":               dataSourceAliyunDcdnDomains(),
+			"aliyun_dcdn_domain_stats":          dataSourceAliyunDcdnDomainStats(),
+			"aliyun_dcdn_domain_logs":           dataSourceAliyunDcdnDomainLogs(),
+			"aliyun_ssl_certificates":           dataSourceAliyunSslCertificates(),
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
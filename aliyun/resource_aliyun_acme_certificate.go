@@ -0,0 +1,371 @@
+package aliyun
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"golang.org/x/crypto/acme"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func resourceAliyunAcmeCertificate() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   resourceAliyunAcmeCertificateRead,
+		CreateContext: resourceAliyunAcmeCertificateCreate,
+		UpdateContext: resourceAliyunAcmeCertificateUpdate,
+		DeleteContext: resourceAliyunAcmeCertificateDelete,
+		CustomizeDiff: resourceAliyunAcmeCertificateCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"directory_url": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "https://acme-v02.api.letsencrypt.org/directory",
+			},
+			"directory_ca_pem": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateCertificateChainPem,
+			},
+			"account_key_pem": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				Sensitive:        true,
+				ValidateDiagFunc: validatePrivateKeyPem,
+			},
+			"email": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"common_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"subject_alternative_names": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"key_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "P256",
+				ValidateFunc: validation.StringInSlice([]string{"P256", "P384", "RSA2048", "RSA4096"}, false),
+			},
+			"dns_provider": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "alidns",
+				ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
+					if _, ok := acmeDnsProviders[v.(string)]; !ok {
+						errors = append(errors, fmt.Errorf("%q: unknown DNS provider %q", k, v.(string)))
+					}
+					return
+				},
+			},
+			"dns_resolvers": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"min_days_remaining": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      30,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"ready_for_renewal": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"certificate_url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"certificate_pem": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"issuer_pem": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"fullchain_pem": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"private_key_pem": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"not_after": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+		},
+	}
+}
+
+func resourceAliyunAcmeCertificateCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Get("ready_for_renewal").(bool) {
+		if err := d.SetNew("ready_for_renewal", false); err != nil {
+			return err
+		}
+		if err := d.ForceNew("ready_for_renewal"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// The certificate is only dropped from state. ACME has no notion of deleting
+// a certificate and revoking it on destroy would break a replacement that is
+// still being rolled out.
+func resourceAliyunAcmeCertificateDelete(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	d.SetId("")
+
+	return diags
+}
+
+// Only arguments used while issuing or checking for renewal can change in
+// place, they take effect on the next issuance.
+func resourceAliyunAcmeCertificateUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return resourceAliyunAcmeCertificateRead(ctx, d, m)
+}
+
+func resourceAliyunAcmeCertificateCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	newProvider, ok := acmeDnsProviders[d.Get("dns_provider").(string)]
+	if !ok {
+		return diag.Errorf("unknown DNS provider %q", d.Get("dns_provider").(string))
+	}
+	dnsProvider, err := newProvider(m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	client, err := newAcmeClient(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+
+	account := &acme.Account{}
+	if v, ok := d.GetOk("email"); ok {
+		account.Contact = []string{"mailto:" + v.(string)}
+	}
+	if _, err := client.Register(ctx, account, acme.AcceptTOS); err != nil && !errors.Is(err, acme.ErrAccountAlreadyExists) {
+		return diag.Errorf("error registering ACME account: %s", err)
+	}
+
+	names := []string{d.Get("common_name").(string)}
+	for _, v := range d.Get("subject_alternative_names").(*schema.Set).List() {
+		if v.(string) != names[0] {
+			names = append(names, v.(string))
+		}
+	}
+
+	order, err := client.AuthorizeOrder(ctx, acme.DomainIDs(names...))
+	if err != nil {
+		return diag.Errorf("error creating ACME order for %s: %s", strings.Join(names, ", "), err)
+	}
+
+	orderURI := order.URI
+	for _, authzURL := range order.AuthzURLs {
+		if err := completeAcmeDns01Authorization(ctx, client, dnsProvider, authzURL, expandStringList(d.Get("dns_resolvers").([]interface{}))); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	// WaitOrder returns no order on failure, hence orderURI.
+	order, err = client.WaitOrder(ctx, orderURI)
+	if err != nil {
+		return diag.Errorf("error waiting for ACME order %s: %s", orderURI, err)
+	}
+
+	key, err := generatePrivateKey(d.Get("key_type").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: names[0]},
+		DNSNames: names,
+	}, key)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	der, certURL, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return diag.Errorf("error finalizing ACME order %s: %s", orderURI, err)
+	}
+
+	keyPem, err := encodePrivateKeyPem(key)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	pems := make([]string, len(der))
+	for i, b := range der {
+		pems[i] = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: b}))
+	}
+
+	fingerprint := sha256.Sum256(der[0])
+	d.SetId(hex.EncodeToString(fingerprint[:]))
+	d.Set("certificate_url", certURL)
+	d.Set("certificate_pem", pems[0])
+	d.Set("issuer_pem", strings.Join(pems[1:], ""))
+	d.Set("fullchain_pem", strings.Join(pems, ""))
+	d.Set("private_key_pem", keyPem)
+
+	return resourceAliyunAcmeCertificateRead(ctx, d, m)
+}
+
+// The certificate is never fetched back from the ACME server, Read only
+// derives the expiry and renewal status from the stored certificate.
+func resourceAliyunAcmeCertificateRead(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	cert, err := parseCertificatePem(d.Get("certificate_pem").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if cert == nil {
+		log.Printf("[WARN] ACME certificate %s has no certificate in state, removing from state", d.Id())
+		d.SetId("")
+		return diags
+	}
+
+	minRemaining := time.Duration(d.Get("min_days_remaining").(int)) * 24 * time.Hour
+	readyForRenewal := time.Until(cert.NotAfter) < minRemaining
+	if readyForRenewal {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "ACME certificate is due for renewal",
+			Detail:   fmt.Sprintf("The certificate for %s expires on %s, within min_days_remaining (%d). A new certificate will be issued.", d.Get("common_name").(string), cert.NotAfter.UTC().Format(time.RFC3339), d.Get("min_days_remaining").(int)),
+		})
+	}
+
+	d.Set("not_after", cert.NotAfter.UTC().Format(time.RFC3339))
+	d.Set("ready_for_renewal", readyForRenewal)
+
+	return diags
+}
+
+func newAcmeClient(d *schema.ResourceData) (*acme.Client, error) {
+	key, err := parsePrivateKey(d.Get("account_key_pem").(string))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing account_key_pem: %s", err)
+	}
+
+	client := &acme.Client{
+		Key:          key,
+		DirectoryURL: d.Get("directory_url").(string),
+		UserAgent:    "terraform-provider-aliyun",
+	}
+
+	if v, ok := d.GetOk("directory_ca_pem"); ok {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(v.(string))) {
+			return nil, fmt.Errorf("directory_ca_pem holds no certificate")
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+		client.HTTPClient = &http.Client{Transport: transport}
+	}
+
+	return client, nil
+}
+
+// completeAcmeDns01Authorization answers the dns-01 challenge of a pending
+// authorization. The TXT record is removed again whatever the outcome.
+func completeAcmeDns01Authorization(ctx context.Context, client *acme.Client, dnsProvider acmeDnsProvider, authzURL string, resolvers []string) error {
+	authz, err := client.GetAuthorization(ctx, authzURL)
+	if err != nil {
+		return fmt.Errorf("error getting ACME authorization %s: %s", authzURL, err)
+	}
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+
+	var challenge *acme.Challenge
+	for _, c := range authz.Challenges {
+		if c.Type == "dns-01" {
+			challenge = c
+			break
+		}
+	}
+	if challenge == nil {
+		return fmt.Errorf("ACME server offers no dns-01 challenge for %s", authz.Identifier.Value)
+	}
+
+	value, err := client.DNS01ChallengeRecord(challenge.Token)
+	if err != nil {
+		return err
+	}
+
+	fqdn := "_acme-challenge." + authz.Identifier.Value + "."
+	handle, err := dnsProvider.Present(fqdn, value)
+	if err != nil {
+		return fmt.Errorf("error creating TXT record %s: %s", fqdn, err)
+	}
+	defer func() {
+		if err := dnsProvider.CleanUp(handle); err != nil {
+			log.Printf("[WARN] error removing TXT record %s: %s", fqdn, err)
+		}
+	}()
+
+	err = resource.RetryContext(ctx, 10*time.Minute, func() *resource.RetryError {
+		records, err := acmeLookupTxt(ctx, fqdn, resolvers)
+		if err != nil {
+			return resource.RetryableError(err)
+		}
+		for _, record := range records {
+			if record == value {
+				return nil
+			}
+		}
+		return resource.RetryableError(fmt.Errorf("TXT record %s has not propagated yet", fqdn))
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.Accept(ctx, challenge); err != nil {
+		return fmt.Errorf("error accepting ACME challenge for %s: %s", authz.Identifier.Value, err)
+	}
+	if _, err := client.WaitAuthorization(ctx, authz.URI); err != nil {
+		return fmt.Errorf("error waiting for ACME authorization of %s: %s", authz.Identifier.Value, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,401 @@
+package aliyun
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/crypto/acme"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryAcmeDnsProvider keeps challenge records in memory instead of
+// publishing them.
+type memoryAcmeDnsProvider struct {
+	mu      sync.Mutex
+	records map[string][2]string
+	next    int
+}
+
+func (p *memoryAcmeDnsProvider) Present(fqdn, value string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.next++
+	handle := strconv.Itoa(p.next)
+	p.records[handle] = [2]string{fqdn, value}
+
+	return handle, nil
+}
+
+func (p *memoryAcmeDnsProvider) CleanUp(handle string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.records, handle)
+
+	return nil
+}
+
+func (p *memoryAcmeDnsProvider) lookupTxt(_ context.Context, fqdn string, _ []string) ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var values []string
+	for _, record := range p.records {
+		if record[0] == fqdn {
+			values = append(values, record[1])
+		}
+	}
+
+	return values, nil
+}
+
+// fakeAcmeServer is an ACME directory issuing from a throwaway CA. It checks
+// dns-01 challenges against the memory DNS provider, and fails every order
+// once authorized when failOrders is set.
+type fakeAcmeServer struct {
+	*httptest.Server
+
+	dns        *memoryAcmeDnsProvider
+	accountKey crypto.PublicKey
+	failOrders bool
+
+	caKey  *ecdsa.PrivateKey
+	caCert *x509.Certificate
+
+	mu     sync.Mutex
+	nonce  int
+	order  map[string]interface{}
+	authzs []map[string]interface{}
+	certs  [][]byte
+}
+
+func newFakeAcmeServer(t *testing.T, dns *memoryAcmeDnsProvider, accountKey crypto.PublicKey) *fakeAcmeServer {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Fake ACME CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, caKey.Public(), caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &fakeAcmeServer{dns: dns, accountKey: accountKey, caKey: caKey, caCert: caCert}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *fakeAcmeServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nonce++
+	w.Header().Set("Replay-Nonce", "nonce-"+strconv.Itoa(s.nonce))
+
+	path := strings.Trim(r.URL.Path, "/")
+	parts := strings.Split(path, "/")
+	switch {
+	case path == "directory":
+		s.writeJSON(w, http.StatusOK, map[string]interface{}{
+			"newNonce":   s.URL + "/nonce",
+			"newAccount": s.URL + "/account",
+			"newOrder":   s.URL + "/order",
+			"revokeCert": s.URL + "/revoke",
+			"keyChange":  s.URL + "/key-change",
+		})
+	case path == "nonce":
+		w.WriteHeader(http.StatusOK)
+	case path == "account":
+		w.Header().Set("Location", s.URL+"/account/1")
+		s.writeJSON(w, http.StatusCreated, map[string]interface{}{"status": "valid"})
+	case path == "order":
+		var req struct {
+			Identifiers []map[string]string `json:"identifiers"`
+		}
+		if err := s.readPayload(r, &req); err != nil {
+			s.writeProblem(w, err)
+			return
+		}
+		var authzURLs []string
+		s.authzs = nil
+		for i, id := range req.Identifiers {
+			s.authzs = append(s.authzs, map[string]interface{}{
+				"status":     "pending",
+				"identifier": id,
+				"challenges": []map[string]interface{}{{
+					"type":   "dns-01",
+					"url":    fmt.Sprintf("%s/challenge/%d", s.URL, i),
+					"token":  fmt.Sprintf("token-%d", i),
+					"status": "pending",
+				}},
+			})
+			authzURLs = append(authzURLs, fmt.Sprintf("%s/authz/%d", s.URL, i))
+		}
+		s.order = map[string]interface{}{
+			"status":         "pending",
+			"identifiers":    req.Identifiers,
+			"authorizations": authzURLs,
+			"finalize":       s.URL + "/finalize/1",
+		}
+		w.Header().Set("Location", s.URL+"/order/1")
+		s.writeJSON(w, http.StatusCreated, s.order)
+	case len(parts) == 2 && parts[0] == "authz":
+		i, _ := strconv.Atoi(parts[1])
+		s.writeJSON(w, http.StatusOK, s.authzs[i])
+	case len(parts) == 2 && parts[0] == "challenge":
+		i, _ := strconv.Atoi(parts[1])
+		s.validateChallenge(i)
+		s.writeJSON(w, http.StatusOK, s.authzs[i]["challenges"].([]map[string]interface{})[0])
+	case path == "order/1":
+		w.Header().Set("Location", s.URL+"/order/1")
+		s.writeJSON(w, http.StatusOK, s.order)
+	case path == "finalize/1":
+		var req struct {
+			CSR string `json:"csr"`
+		}
+		if err := s.readPayload(r, &req); err != nil {
+			s.writeProblem(w, err)
+			return
+		}
+		if err := s.issue(req.CSR); err != nil {
+			s.writeProblem(w, err)
+			return
+		}
+		w.Header().Set("Location", s.URL+"/order/1")
+		s.writeJSON(w, http.StatusOK, s.order)
+	case path == "cert/1":
+		w.Header().Set("Content-Type", "application/pem-certificate-chain")
+		for _, der := range s.certs {
+			pem.Encode(w, &pem.Block{Type: "CERTIFICATE", Bytes: der})
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// validateChallenge looks up the TXT record answering challenge i and
+// updates the order once every authorization is settled.
+func (s *fakeAcmeServer) validateChallenge(i int) {
+	authz := s.authzs[i]
+	challenge := authz["challenges"].([]map[string]interface{})[0]
+
+	thumbprint, _ := acme.JWKThumbprint(s.accountKey)
+	digest := sha256.Sum256([]byte(challenge["token"].(string) + "." + thumbprint))
+	want := base64.RawURLEncoding.EncodeToString(digest[:])
+
+	fqdn := "_acme-challenge." + authz["identifier"].(map[string]string)["value"] + "."
+	values, _ := s.dns.lookupTxt(context.Background(), fqdn, nil)
+	status := "invalid"
+	for _, value := range values {
+		if value == want {
+			status = "valid"
+		}
+	}
+	challenge["status"] = status
+	authz["status"] = status
+
+	for _, authz := range s.authzs {
+		switch authz["status"] {
+		case "pending":
+			return
+		case "invalid":
+			s.order["status"] = "invalid"
+			return
+		}
+	}
+	if s.failOrders {
+		s.order["status"] = "invalid"
+	} else {
+		s.order["status"] = "ready"
+	}
+}
+
+func (s *fakeAcmeServer) issue(csrB64 string) error {
+	if s.order["status"] != "ready" {
+		return fmt.Errorf("order is %s", s.order["status"])
+	}
+
+	der, err := base64.RawURLEncoding.DecodeString(csrB64)
+	if err != nil {
+		return err
+	}
+	csr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		return err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      csr.Subject,
+		DNSNames:     csr.DNSNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(90 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	leaf, err := x509.CreateCertificate(rand.Reader, template, s.caCert, csr.PublicKey, s.caKey)
+	if err != nil {
+		return err
+	}
+
+	s.certs = [][]byte{leaf, s.caCert.Raw}
+	s.order["status"] = "valid"
+	s.order["certificate"] = s.URL + "/cert/1"
+
+	return nil
+}
+
+func (s *fakeAcmeServer) readPayload(r *http.Request, v interface{}) error {
+	var jws struct {
+		Payload string `json:"payload"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&jws); err != nil {
+		return err
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(jws.Payload)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(payload, v)
+}
+
+func (s *fakeAcmeServer) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func (s *fakeAcmeServer) writeProblem(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{
+		"type":   "urn:ietf:params:acme:error:malformed",
+		"detail": err.Error(),
+	})
+}
+
+// testAcmeCertificateResourceData registers the memory DNS provider and
+// returns the arguments of a certificate issued by a fake ACME server.
+func testAcmeCertificateResourceData(t *testing.T) (*schema.ResourceData, *fakeAcmeServer, *memoryAcmeDnsProvider) {
+	dns := &memoryAcmeDnsProvider{records: map[string][2]string{}}
+	acmeDnsProviders["memory"] = func(interface{}) (acmeDnsProvider, error) {
+		return dns, nil
+	}
+	acmeLookupTxt = dns.lookupTxt
+	t.Cleanup(func() {
+		delete(acmeDnsProviders, "memory")
+		acmeLookupTxt = lookupTxt
+	})
+
+	accountKey, err := generatePrivateKey("P256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountKeyPem, err := encodePrivateKeyPem(accountKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := newFakeAcmeServer(t, dns, accountKey.Public())
+
+	d := schema.TestResourceDataRaw(t, resourceAliyunAcmeCertificate().Schema, map[string]interface{}{
+		"directory_url":             server.URL + "/directory",
+		"directory_ca_pem":          string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})),
+		"account_key_pem":           accountKeyPem,
+		"common_name":               "example.com",
+		"subject_alternative_names": []interface{}{"www.example.com"},
+		"dns_provider":              "memory",
+	})
+
+	return d, server, dns
+}
+
+func TestResourceAliyunAcmeCertificateCreate(t *testing.T) {
+	d, server, dns := testAcmeCertificateResourceData(t)
+
+	diags := resourceAliyunAcmeCertificateCreate(context.Background(), d, nil)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	cert, err := parseCertificatePem(d.Get("certificate_pem").(string))
+	if err != nil || cert == nil {
+		t.Fatalf("certificate_pem does not hold a certificate: %v", err)
+	}
+	if err := cert.CheckSignatureFrom(server.caCert); err != nil {
+		t.Errorf("certificate is not issued by the ACME CA: %s", err)
+	}
+	if !equalStringSlices(cert.DNSNames, []string{"example.com", "www.example.com"}) {
+		t.Errorf("certificate is issued for %v", cert.DNSNames)
+	}
+	if d.Get("fullchain_pem").(string) != d.Get("certificate_pem").(string)+d.Get("issuer_pem").(string) {
+		t.Errorf("fullchain_pem is not certificate_pem followed by issuer_pem")
+	}
+	if diags := checkCertificateForDomain(d.Get("certificate_pem").(string), d.Get("private_key_pem").(string), "example.com"); diags.HasError() {
+		t.Errorf("private_key_pem does not match the certificate: %v", diags)
+	}
+	fingerprint := sha256.Sum256(cert.Raw)
+	if d.Id() != fmt.Sprintf("%x", fingerprint) {
+		t.Errorf("id %s is not the certificate fingerprint", d.Id())
+	}
+	if d.Get("certificate_url").(string) != server.URL+"/cert/1" {
+		t.Errorf("certificate_url is %s", d.Get("certificate_url").(string))
+	}
+	if d.Get("ready_for_renewal").(bool) {
+		t.Errorf("a new certificate is ready for renewal")
+	}
+	if len(dns.records) != 0 {
+		t.Errorf("challenge records are left behind: %v", dns.records)
+	}
+}
+
+func TestResourceAliyunAcmeCertificateCreateOrderFailure(t *testing.T) {
+	d, server, dns := testAcmeCertificateResourceData(t)
+	server.failOrders = true
+
+	diags := resourceAliyunAcmeCertificateCreate(context.Background(), d, nil)
+	if !diags.HasError() {
+		t.Fatalf("expected an error for an invalid order")
+	}
+	if want := "error waiting for ACME order " + server.URL + "/order/1"; !strings.Contains(diags[0].Summary, want) {
+		t.Errorf("error %q does not contain %q", diags[0].Summary, want)
+	}
+	if d.Id() != "" {
+		t.Errorf("id %s is set for a failed order", d.Id())
+	}
+	if len(dns.records) != 0 {
+		t.Errorf("challenge records are left behind: %v", dns.records)
+	}
+}
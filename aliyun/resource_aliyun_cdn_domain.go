@@ -0,0 +1,296 @@
+package aliyun
+
+import (
+	"context"
+	"fmt"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/cdn"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"time"
+)
+
+func resourceAliyunCdnDomain() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   resourceAliyunCdnDomainRead,
+		CreateContext: resourceAliyunCdnDomainCreate,
+		UpdateContext: resourceAliyunCdnDomainUpdate,
+		DeleteContext: resourceAliyunCdnDomainDelete,
+		CustomizeDiff: customizeDomainSourcesDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"resource_group_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"domain_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"cdn_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"web", "download", "video"}, false),
+			},
+			"scope": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"domestic", "global", "overseas"}, false),
+				Default:      "domestic",
+			},
+			"cname": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"sources": domainSourcesSchema(),
+			// Set before removing this resource in favour of an
+			// aliyun_dcdn_domain with migrate_from_cdn, so the domain is never
+			// deleted should Terraform destroy it before the migration.
+			"migrate_to_dcdn": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+	}
+}
+
+// Migrating a domain to DCDN takes two applies: first set migrate_to_dcdn,
+// then replace this resource with an aliyun_dcdn_domain with
+// migrate_from_cdn. A domain with migrate_to_dcdn set, or already migrated,
+// is only removed from state.
+func resourceAliyunCdnDomainDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).cdnconn
+
+	if d.Get("migrate_to_dcdn").(bool) {
+		log.Printf("[WARN] CDN domain %s is being migrated to DCDN, removing from state without deleting it", d.Id())
+		d.SetId("")
+		return diags
+	}
+
+	dcdnRequest := dcdn.CreateDescribeDcdnDomainDetailRequest()
+	dcdnRequest.DomainName = d.Id()
+
+	_, err := m.(Client).dcdnconn.DescribeDcdnDomainDetail(dcdnRequest)
+	if err == nil {
+		log.Printf("[WARN] CDN domain %s has been migrated to DCDN, removing from state without deleting it", d.Id())
+		d.SetId("")
+		return diags
+	}
+	if !IsExpectedErrors(err, []string{"InvalidDomain.NotFound"}) {
+		return diag.FromErr(err)
+	}
+
+	request := cdn.CreateDeleteCdnDomainRequest()
+	request.DomainName = d.Id()
+
+	_, err = conn.DeleteCdnDomain(request)
+	if err != nil {
+		if IsExpectedErrors(err, []string{"InvalidDomain.NotFound"}) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	err = waitForCdnDomainStatus(ctx, conn, d.Id(), domainDeletePendingStatus, []string{}, d.Timeout(schema.TimeoutDelete))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func resourceAliyunCdnDomainUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn := m.(Client).cdnconn
+
+	o, _ := d.GetChange("enabled")
+	enabled := o.(bool)
+	if d.HasChange("enabled") && d.Get("enabled").(bool) {
+		if err := setCdnDomainEnabled(ctx, conn, d.Id(), true, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.FromErr(err)
+		}
+		enabled = true
+	}
+
+	if d.HasChange("scope") {
+		request := cdn.CreateModifyCdnDomainSchdmByPropertyRequest()
+		request.DomainName = d.Id()
+		request.Property = fmt.Sprintf(`{"coverage":"%s"}`, d.Get("scope").(string))
+		_, err := conn.ModifyCdnDomainSchdmByProperty(request)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	updateDomain := false
+	request := cdn.CreateModifyCdnDomainRequest()
+	if d.HasChange("resource_group_id") {
+		updateDomain = true
+		request.ResourceGroupId = d.Get("resource_group_id").(string)
+	}
+	if d.HasChange("sources") {
+		updateDomain = true
+		sources, err := convertSourcesToString(d.Get("sources").(*schema.Set).List())
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		request.Sources = sources
+	}
+
+	if updateDomain {
+		request.DomainName = d.Id()
+		_, err := conn.ModifyCdnDomain(request)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		err = waitForCdnDomainStatus(ctx, conn, d.Id(), domainPendingStatus, []string{domainTargetStatus(enabled)}, d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("enabled") && !d.Get("enabled").(bool) {
+		if err := setCdnDomainEnabled(ctx, conn, d.Id(), false, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceAliyunCdnDomainRead(ctx, d, m)
+}
+
+func resourceAliyunCdnDomainCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn := m.(Client).cdnconn
+
+	domain := d.Get("domain_name").(string)
+
+	request := cdn.CreateAddCdnDomainRequest()
+	request.DomainName = domain
+	request.CdnType = d.Get("cdn_type").(string)
+
+	if v, ok := d.GetOk("resource_group_id"); ok {
+		request.ResourceGroupId = v.(string)
+	}
+
+	if v, ok := d.GetOk("scope"); ok {
+		request.Scope = v.(string)
+	}
+
+	sources, err := convertSourcesToString(d.Get("sources").(*schema.Set).List())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	request.Sources = sources
+
+	_, err = conn.AddCdnDomain(request)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(domain)
+
+	err = waitForCdnDomainStatus(ctx, conn, domain, domainPendingStatus, []string{"online"}, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if !d.Get("enabled").(bool) {
+		if err := setCdnDomainEnabled(ctx, conn, domain, false, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceAliyunCdnDomainRead(ctx, d, m)
+}
+
+func resourceAliyunCdnDomainRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).cdnconn
+
+	request := cdn.CreateDescribeCdnDomainDetailRequest()
+	request.DomainName = d.Id()
+
+	res, err := conn.DescribeCdnDomainDetail(request)
+	if err != nil {
+		if IsExpectedErrors(err, []string{"InvalidDomain.NotFound"}) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	d.Set("domain_name", d.Id())
+	d.Set("resource_group_id", res.GetDomainDetailModel.ResourceGroupId)
+	d.Set("cdn_type", res.GetDomainDetailModel.CdnType)
+	d.Set("scope", res.GetDomainDetailModel.Scope)
+	if err := d.Set("sources", flattenCdnSources(res.GetDomainDetailModel.SourceModels.SourceModel)); err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("cname", res.GetDomainDetailModel.Cname)
+	d.Set("enabled", res.GetDomainDetailModel.DomainStatus != "offline")
+
+	return diags
+}
+
+func setCdnDomainEnabled(ctx context.Context, conn *cdn.Client, domain string, enabled bool, timeout time.Duration) error {
+	if enabled {
+		request := cdn.CreateStartCdnDomainRequest()
+		request.DomainName = domain
+		if _, err := conn.StartCdnDomain(request); err != nil {
+			return err
+		}
+		return waitForCdnDomainStatus(ctx, conn, domain, domainStartPendingStatus, []string{"online"}, timeout)
+	}
+
+	request := cdn.CreateStopCdnDomainRequest()
+	request.DomainName = domain
+	if _, err := conn.StopCdnDomain(request); err != nil {
+		return err
+	}
+	return waitForCdnDomainStatus(ctx, conn, domain, domainStopPendingStatus, []string{"offline"}, timeout)
+}
+
+func cdnDomainStateRefreshFunc(conn *cdn.Client, domain string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		request := cdn.CreateDescribeCdnDomainDetailRequest()
+		request.DomainName = domain
+
+		res, err := conn.DescribeCdnDomainDetail(request)
+		if err != nil {
+			if IsExpectedErrors(err, []string{"InvalidDomain.NotFound"}) {
+				return nil, "", nil
+			}
+			return nil, "", err
+		}
+
+		return res, res.GetDomainDetailModel.DomainStatus, nil
+	}
+}
+
+func waitForCdnDomainStatus(ctx context.Context, conn *cdn.Client, domain string, pending, target []string, timeout time.Duration) error {
+	return waitForDomainStatus(ctx, "cdn", domain, cdnDomainStateRefreshFunc(conn, domain), pending, target, timeout)
+}
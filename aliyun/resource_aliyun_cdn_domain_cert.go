@@ -0,0 +1,208 @@
+package aliyun
+
+import (
+	"context"
+	"fmt"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/cdn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceAliyunCdnDomainCert() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   resourceAliyunCdnDomainCertRead,
+		CreateContext: resourceAliyunCdnDomainCertCreate,
+		UpdateContext: resourceAliyunCdnDomainCertUpdate,
+		DeleteContext: resourceAliyunCdnDomainCertDelete,
+		CustomizeDiff: resourceAliyunCdnDomainCertCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"domain_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"cert_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"cert_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "upload",
+				ValidateFunc: validation.StringInSlice([]string{"upload", "cas"}, false),
+			},
+			"ssl_pub": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateCertificateChainPem,
+			},
+			"ssl_pri": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				ConflictsWith:    []string{"ssl_pri_file", "ssl_pri_env"},
+				ValidateDiagFunc: validatePrivateKeyPem,
+				StateFunc:        sha256Hex,
+			},
+			"ssl_pri_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"ssl_pri", "ssl_pri_env"},
+			},
+			"ssl_pri_env": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"ssl_pri", "ssl_pri_file"},
+			},
+			"ssl_pri_sha256": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"not_after": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"issuer": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"subject_alt_names": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"renew_before_days": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"ready_for_renewal": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceAliyunCdnDomainCertCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if err := customizeDomainCertDiff(d); err != nil {
+		return err
+	}
+
+	if d.GetRawConfig().IsNull() {
+		return nil
+	}
+
+	// CDN refers to CAS certificates by name only.
+	if d.Get("cert_type").(string) == "cas" && d.NewValueKnown("cert_name") && d.Get("cert_name").(string) == "" {
+		return fmt.Errorf("cert_name is required when cert_type is cas")
+	}
+
+	return nil
+}
+
+func resourceAliyunCdnDomainCertDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).cdnconn
+
+	request := cdn.CreateSetDomainServerCertificateRequest()
+	request.DomainName = d.Id()
+	request.ServerCertificateStatus = "off"
+
+	_, err := conn.SetDomainServerCertificate(request)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func resourceAliyunCdnDomainCertUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChanges("cert_name", "cert_type", "ssl_pub", "ssl_pri_sha256") {
+		if diags := checkDomainCertificate(d); diags.HasError() {
+			return diags
+		}
+		if err := setCdnDomainCertificate(d, m); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceAliyunCdnDomainCertRead(ctx, d, m)
+}
+
+func resourceAliyunCdnDomainCertCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if diags := checkDomainCertificate(d); diags.HasError() {
+		return diags
+	}
+	if err := setCdnDomainCertificate(d, m); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(d.Get("domain_name").(string))
+
+	return resourceAliyunCdnDomainCertRead(ctx, d, m)
+}
+
+func resourceAliyunCdnDomainCertRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).cdnconn
+
+	request := cdn.CreateDescribeDomainCertificateInfoRequest()
+	request.DomainName = d.Id()
+
+	res, err := conn.DescribeDomainCertificateInfo(request)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if len(res.CertInfos.CertInfo) == 0 || res.CertInfos.CertInfo[0].ServerCertificateStatus == "off" {
+		d.SetId("")
+		return diags
+	}
+
+	certInfo := res.CertInfos.CertInfo[0]
+
+	d.Set("domain_name", d.Id())
+	d.Set("cert_name", certInfo.CertName)
+	if certInfo.CertType == "upload" || certInfo.CertType == "cas" {
+		d.Set("cert_type", certInfo.CertType)
+	}
+
+	return append(diags, setDomainCertificateDetails(d, "CDN", certInfo.Issuer, certInfo.ServerCertificate, certInfo.CertExpireTime)...)
+}
+
+// setCdnDomainCertificate deploys the configured certificate with ForceSet,
+// replacing whatever certificate the domain serves without turning HTTPS off.
+func setCdnDomainCertificate(d *schema.ResourceData, m interface{}) error {
+	conn := m.(Client).cdnconn
+
+	request := cdn.CreateSetDomainServerCertificateRequest()
+	request.DomainName = d.Get("domain_name").(string)
+	request.CertName = d.Get("cert_name").(string)
+	request.CertType = d.Get("cert_type").(string)
+	request.ForceSet = "1"
+	request.ServerCertificateStatus = "on"
+
+	if request.CertType == "upload" {
+		key, _, err := domainCertPrivateKey(d)
+		if err != nil {
+			return err
+		}
+		request.ServerCertificate = d.Get("ssl_pub").(string)
+		request.PrivateKey = key
+	}
+
+	_, err := conn.SetDomainServerCertificate(request)
+
+	return err
+}
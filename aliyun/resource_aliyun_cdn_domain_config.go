@@ -0,0 +1,193 @@
+package aliyun
+
+import (
+	"context"
+	"fmt"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/cdn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"time"
+)
+
+func resourceAliyunCdnDomainConfig() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAliyunCdnDomainConfigCreate,
+		ReadContext:   resourceAliyunCdnDomainConfigRead,
+		UpdateContext: resourceAliyunCdnDomainConfigUpdate,
+		DeleteContext: resourceAliyunCdnDomainConfigDelete,
+		CustomizeDiff: customizeDomainConfigArgsDiff,
+
+		Schema: map[string]*schema.Schema{
+			"domain_name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringLenBetween(5, 67),
+			},
+			"function_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"function_args": {
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"arg_name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"arg_value": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+					},
+				},
+			},
+			"config_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+		},
+	}
+}
+
+func resourceAliyunCdnDomainConfigDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).cdnconn
+
+	domain, functionName, configId, err := parseDomainConfigId(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	configs, err := describeCdnDomainConfigs(conn, domain, []string{functionName})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	config, ok := findDomainConfig(configs, configId)
+	if !ok {
+		d.SetId("")
+		return diags
+	}
+
+	deleteRequest := cdn.CreateDeleteSpecificConfigRequest()
+	deleteRequest.ConfigId = config.ConfigId
+	deleteRequest.DomainName = domain
+
+	_, err = conn.DeleteSpecificConfig(deleteRequest)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func resourceAliyunCdnDomainConfigRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).cdnconn
+
+	domain, functionName, configId, err := parseDomainConfigId(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	configs, err := describeCdnDomainConfigs(conn, domain, []string{functionName})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	config, ok := findDomainConfig(configs, configId)
+	if !ok {
+		d.SetId("")
+		return diags
+	}
+
+	var funArgs []map[string]string
+
+	for name, value := range config.Args {
+		if name == "cert" || name == "cert_id" || name == "cert_name" || name == "cert_type" || name == "dkey" || name == "pkey" || name == "https" {
+			continue
+		}
+		funArgs = append(funArgs, map[string]string{
+			"arg_name":  name,
+			"arg_value": value,
+		})
+	}
+
+	d.SetId(fmt.Sprintf("%s%s%s%s%s", domain, COLON_SEPARATED, functionName, COLON_SEPARATED, config.ConfigId))
+	d.Set("domain_name", domain)
+	d.Set("function_name", functionName)
+	d.Set("function_args", funArgs)
+	d.Set("config_id", config.ConfigId)
+	d.Set("status", config.Status)
+
+	return diags
+}
+
+func resourceAliyunCdnDomainConfigUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn := m.(Client).cdnconn
+
+	if d.HasChange("function_args") {
+		domain, functionName, configId, err := parseDomainConfigId(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		function, err := domainFunction(functionName, configId, convertFunctionArgsToMap(d.Get("function_args").(*schema.Set).List()))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		functions := []map[string]interface{}{function}
+
+		configIds, err := setCdnDomainFunctions(ctx, conn, domain, functions, d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		err = waitForCdnDomainConfigs(ctx, conn, domain, functions, configIds, d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceAliyunCdnDomainConfigRead(ctx, d, m)
+}
+
+func resourceAliyunCdnDomainConfigCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn := m.(Client).cdnconn
+
+	domain := d.Get("domain_name").(string)
+	functionName := d.Get("function_name").(string)
+
+	function, err := domainFunction(functionName, "", convertFunctionArgsToMap(d.Get("function_args").(*schema.Set).List()))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	functions := []map[string]interface{}{function}
+
+	configIds, err := setCdnDomainFunctions(ctx, conn, domain, functions, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s%s%s%s%s", domain, COLON_SEPARATED, functionName, COLON_SEPARATED, configIds[0]))
+	d.Set("config_id", configIds[0])
+
+	err = waitForCdnDomainConfigs(ctx, conn, domain, functions, configIds, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceAliyunCdnDomainConfigRead(ctx, d, m)
+}
@@ -0,0 +1,385 @@
+package aliyun
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"net/netip"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DCDN rejects rules whose list argument grows too long, so large lists are
+// spread over several rules of the same function. The API reference gives no
+// figure for it; the bound is on the joined argument, since that is what is
+// sent, and keeps each rule well below the size of a request.
+const dcdnAccessListMaxLength = 4000
+
+type dcdnAccessList struct {
+	attribute    string
+	functionName string
+	argName      string
+	separator    string
+}
+
+var dcdnAccessLists = []dcdnAccessList{
+	{"ip_allow", "ip_allow_list_set", "ip_list", ","},
+	{"ip_deny", "ip_black_list_set", "ip_list", ","},
+	{"referer_allow", "referer_white_list_set", "refer_domain_allow_list", ","},
+	{"referer_deny", "referer_black_list_set", "refer_domain_deny_list", ","},
+	{"ua_deny", "ali_ua", "ua", "|"},
+}
+
+func resourceAliyunDcdnAccessControl() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   resourceAliyunDcdnAccessControlRead,
+		CreateContext: resourceAliyunDcdnAccessControlCreate,
+		UpdateContext: resourceAliyunDcdnAccessControlUpdate,
+		DeleteContext: resourceAliyunDcdnAccessControlDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importDcdnDomainConfigs(dcdnAccessListFunctionNames()),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"domain_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"ip_allow": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				ConflictsWith: []string{"ip_deny"},
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateIpOrCidr,
+				},
+			},
+			"ip_deny": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				ConflictsWith: []string{"ip_allow"},
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateIpOrCidr,
+				},
+			},
+			"referer_allow": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				ConflictsWith: []string{"referer_deny"},
+				Elem:          &schema.Schema{Type: schema.TypeString},
+			},
+			"referer_deny": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				ConflictsWith: []string{"referer_allow"},
+				Elem:          &schema.Schema{Type: schema.TypeString},
+			},
+			"allow_empty_referer": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"ua_deny": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"config_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+		},
+	}
+}
+
+func resourceAliyunDcdnAccessControlDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	err := deleteDcdnDomainConfigs(conn, d.Id(), expandStringList(d.Get("config_ids").([]interface{})))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func resourceAliyunDcdnAccessControlUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChanges("ip_allow", "ip_deny", "referer_allow", "referer_deny", "allow_empty_referer", "ua_deny") {
+		if err := setDcdnAccessControl(ctx, d, m, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceAliyunDcdnAccessControlRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnAccessControlCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d.SetId(d.Get("domain_name").(string))
+
+	if err := setDcdnAccessControl(ctx, d, m, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceAliyunDcdnAccessControlRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnAccessControlRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	configs, err := describeTrackedDcdnDomainConfigs(conn, d, dcdnAccessListFunctionNames())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	configIds := make([]string, 0, len(configs))
+	for _, config := range configs {
+		configIds = append(configIds, config.ConfigId)
+	}
+
+	remote := make(map[string][]string, len(dcdnAccessLists))
+	allowEmptyReferer := true
+	for _, list := range dcdnAccessLists {
+		for _, config := range configs {
+			if config.FunctionName != list.functionName {
+				continue
+			}
+			args := config.Args
+			if args[list.argName] != "" {
+				remote[list.attribute] = append(remote[list.attribute], strings.Split(args[list.argName], list.separator)...)
+			}
+			if v, ok := args["allow_empty"]; ok {
+				allowEmptyReferer = v == "on"
+			}
+		}
+	}
+
+	d.Set("domain_name", d.Id())
+	d.Set("allow_empty_referer", allowEmptyReferer)
+	if err := d.Set("config_ids", configIds); err != nil {
+		return diag.FromErr(err)
+	}
+	for _, list := range dcdnAccessLists {
+		current, err := normalizeDcdnAccessList(list.attribute, d.Get(list.attribute).([]interface{}))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if equalStringSlices(current, remote[list.attribute]) {
+			continue
+		}
+		if err := d.Set(list.attribute, remote[list.attribute]); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return diags
+}
+
+func setDcdnAccessControl(ctx context.Context, d *schema.ResourceData, m interface{}, timeout time.Duration) error {
+	conn := m.(Client).dcdnconn
+
+	configs, err := describeTrackedDcdnDomainConfigs(conn, d, dcdnAccessListFunctionNames())
+	if err != nil {
+		return err
+	}
+
+	var functions []map[string]interface{}
+	var obsolete []string
+	for _, list := range dcdnAccessLists {
+		var configIds []string
+		for _, config := range configs {
+			if config.FunctionName == list.functionName {
+				configIds = append(configIds, config.ConfigId)
+			}
+		}
+
+		entries, err := normalizeDcdnAccessList(list.attribute, d.Get(list.attribute).([]interface{}))
+		if err != nil {
+			return err
+		}
+
+		var args []map[string]string
+		for _, chunk := range chunkJoinedStrings(entries, list.separator, dcdnAccessListMaxLength) {
+			arg := map[string]string{list.argName: strings.Join(chunk, list.separator)}
+			switch list.functionName {
+			case "referer_white_list_set", "referer_black_list_set":
+				arg["allow_empty"] = OnOff(d.Get("allow_empty_referer").(bool))
+			case "ali_ua":
+				arg["type"] = "black"
+			}
+			args = append(args, arg)
+		}
+
+		f, o, err := reuseDcdnDomainConfigs(list.functionName, configIds, args)
+		if err != nil {
+			return err
+		}
+		functions = append(functions, f...)
+		obsolete = append(obsolete, o...)
+	}
+
+	return setTrackedDcdnDomainConfigs(ctx, conn, d, functions, nil, obsolete, timeout)
+}
+
+func dcdnAccessListFunctionNames() []string {
+	functionNames := make([]string, len(dcdnAccessLists))
+	for i, list := range dcdnAccessLists {
+		functionNames[i] = list.functionName
+	}
+	return functionNames
+}
+
+// normalizeDcdnAccessList deduplicates the entries of an access list. IP
+// lists are additionally collapsed so that no range is covered by another.
+func normalizeDcdnAccessList(attribute string, v []interface{}) ([]string, error) {
+	entries := make([]string, 0, len(v))
+	seen := make(map[string]bool, len(v))
+	for _, vv := range v {
+		entry := strings.TrimSpace(vv.(string))
+		if entry == "" || seen[entry] {
+			continue
+		}
+		seen[entry] = true
+		entries = append(entries, entry)
+	}
+
+	if attribute == "ip_allow" || attribute == "ip_deny" {
+		return collapseCidrs(entries)
+	}
+
+	return entries, nil
+}
+
+func validateIpOrCidr(v interface{}, k string) (ws []string, errors []error) {
+	if _, err := parseIpOrCidr(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%q must be an IP address or CIDR block, got %q: %s", k, v.(string), err))
+	}
+	return
+}
+
+func parseIpOrCidr(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// collapseCidrs drops ranges contained in other ranges and merges adjacent
+// ranges of the same size into their common parent.
+func collapseCidrs(entries []string) ([]string, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		prefix, err := parseIpOrCidr(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP address or CIDR block %q: %s", entry, err)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+
+	sort.Slice(prefixes, func(i, j int) bool {
+		if c := prefixes[i].Addr().Compare(prefixes[j].Addr()); c != 0 {
+			return c < 0
+		}
+		return prefixes[i].Bits() < prefixes[j].Bits()
+	})
+
+	stack := make([]netip.Prefix, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		if n := len(stack); n > 0 && stack[n-1].Overlaps(prefix) {
+			if stack[n-1].Bits() <= prefix.Bits() {
+				continue
+			}
+			stack = stack[:n-1]
+		}
+		stack = append(stack, prefix)
+		for n := len(stack); n > 1; n = len(stack) {
+			a, b := stack[n-2], stack[n-1]
+			if a.Bits() != b.Bits() || a.Bits() == 0 || a.Addr().Is4() != b.Addr().Is4() {
+				break
+			}
+			parent := netip.PrefixFrom(a.Addr(), a.Bits()-1).Masked()
+			if parent != netip.PrefixFrom(b.Addr(), b.Bits()-1).Masked() {
+				break
+			}
+			stack = append(stack[:n-2], parent)
+		}
+	}
+
+	collapsed := make([]string, len(stack))
+	for i, prefix := range stack {
+		if prefix.IsSingleIP() {
+			collapsed[i] = prefix.Addr().String()
+		} else {
+			collapsed[i] = prefix.String()
+		}
+	}
+
+	return collapsed, nil
+}
+
+func chunkStrings(v []string, size int) [][]string {
+	chunks := make([][]string, 0, (len(v)+size-1)/size)
+	for size < len(v) {
+		v, chunks = v[size:], append(chunks, v[:size])
+	}
+	if len(v) > 0 {
+		chunks = append(chunks, v)
+	}
+	return chunks
+}
+
+// chunkJoinedStrings splits v into chunks whose entries joined with separator
+// are at most maxLength long. An entry longer than that gets a chunk of its
+// own.
+func chunkJoinedStrings(v []string, separator string, maxLength int) [][]string {
+	var chunks [][]string
+	start, length := 0, 0
+	for i, s := range v {
+		if i > start && length+len(separator)+len(s) > maxLength {
+			chunks = append(chunks, v[start:i])
+			start, length = i, 0
+		}
+		if i > start {
+			length += len(separator)
+		}
+		length += len(s)
+	}
+	if start < len(v) {
+		chunks = append(chunks, v[start:])
+	}
+	return chunks
+}
+
+func equalStringSlices(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+package aliyun
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCollapseCidrs(t *testing.T) {
+	cases := []struct {
+		name    string
+		entries []string
+		want    []string
+	}{
+		{"duplicate address", []string{"192.0.2.1", "192.0.2.1"}, []string{"192.0.2.1"}},
+		{"address within block", []string{"192.0.2.1", "192.0.2.0/24"}, []string{"192.0.2.0/24"}},
+		{"block within block", []string{"192.0.2.0/24", "192.0.2.128/26"}, []string{"192.0.2.0/24"}},
+		{"sibling blocks", []string{"192.0.2.128/25", "192.0.2.0/25"}, []string{"192.0.2.0/24"}},
+		{"sibling addresses", []string{"192.0.2.0", "192.0.2.1"}, []string{"192.0.2.0/31"}},
+		{"adjacent addresses of different blocks", []string{"192.0.2.1", "192.0.2.2"}, []string{"192.0.2.1", "192.0.2.2"}},
+		{"merges cascade", []string{"192.0.2.0/26", "192.0.2.64/26", "192.0.2.128/25"}, []string{"192.0.2.0/24"}},
+		{"unmasked block", []string{"192.0.2.5/24"}, []string{"192.0.2.0/24"}},
+		{"ipv4 and ipv6", []string{"2001:db8:8000::/33", "198.51.100.0/24", "2001:db8::/33"}, []string{"198.51.100.0/24", "2001:db8::/32"}},
+		{"empty", nil, []string{}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := collapseCidrs(c.entries)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("collapseCidrs(%v) = %v, want %v", c.entries, got, c.want)
+			}
+		})
+	}
+}
+
+func TestCollapseCidrsInvalid(t *testing.T) {
+	if _, err := collapseCidrs([]string{"192.0.2.1", "example.com"}); err == nil {
+		t.Errorf("expected an error for an entry that is not an IP address")
+	}
+}
+
+func TestChunkJoinedStrings(t *testing.T) {
+	cases := []struct {
+		name string
+		v    []string
+		want [][]string
+	}{
+		{"fits exactly", []string{"aaa", "bbb"}, [][]string{{"aaa", "bbb"}}},
+		{"separator overflows", []string{"aa", "bb", "cc"}, [][]string{{"aa", "bb"}, {"cc"}}},
+		{"long entry alone", []string{"aaaaaaaaa", "b"}, [][]string{{"aaaaaaaaa"}, {"b"}}},
+		{"long entry in between", []string{"a", "bbbbbbbbb", "c"}, [][]string{{"a"}, {"bbbbbbbbb"}, {"c"}}},
+		{"empty", nil, nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := chunkJoinedStrings(c.v, ",", 7)
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("chunkJoinedStrings(%v) = %v, want %v", c.v, got, c.want)
+			}
+		})
+	}
+}
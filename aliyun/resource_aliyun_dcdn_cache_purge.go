@@ -0,0 +1,250 @@
+package aliyun
+
+import (
+	"context"
+	"fmt"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var dcdnCacheQuotaErrors = []string{"QuotaExceeded", "QuotaExceeded.Refresh", "QuotaExceeded.Preload"}
+
+func resourceAliyunDcdnCachePurge() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   resourceAliyunDcdnCachePurgeRead,
+		CreateContext: resourceAliyunDcdnCachePurgeCreate,
+		DeleteContext: resourceAliyunDcdnCachePurgeDelete,
+		CustomizeDiff: resourceAliyunDcdnCachePurgeCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "refresh",
+				ValidateFunc: validation.StringInSlice([]string{"refresh", "preload"}, false),
+			},
+			"object_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "File",
+				ValidateFunc: validation.StringInSlice([]string{"File", "Directory", "Regex"}, false),
+			},
+			"paths": {
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"area": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"domestic", "overseas"}, false),
+			},
+			"triggers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"wait_for_completion": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+				Default:  true,
+			},
+			"task_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+	}
+}
+
+func resourceAliyunDcdnCachePurgeCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Get("type").(string) == "preload" && d.Get("object_type").(string) != "File" {
+		return fmt.Errorf("preload only supports object_type File")
+	}
+
+	return nil
+}
+
+func resourceAliyunDcdnCachePurgeDelete(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	d.SetId("")
+
+	return diags
+}
+
+func resourceAliyunDcdnCachePurgeCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn := m.(Client).dcdnconn
+
+	purgeType := d.Get("type").(string)
+	objectType := d.Get("object_type").(string)
+	paths := expandStringList(d.Get("paths").([]interface{}))
+
+	if err := checkDcdnCacheQuota(conn, purgeType, objectType, len(paths)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// RefreshDcdnObjectCaches accepts up to 1000 URLs or 100 directories per
+	// call and PreloadDcdnObjectCaches up to 100 URLs.
+	size := 100
+	if purgeType == "refresh" && objectType == "File" {
+		size = 1000
+	}
+
+	var taskIds []string
+	for _, chunk := range chunkStrings(paths, size) {
+		var taskId string
+		var err error
+		if purgeType == "preload" {
+			request := dcdn.CreatePreloadDcdnObjectCachesRequest()
+			request.ObjectPath = strings.Join(chunk, "\n")
+			request.Area = d.Get("area").(string)
+
+			var res *dcdn.PreloadDcdnObjectCachesResponse
+			res, err = conn.PreloadDcdnObjectCaches(request)
+			if err == nil {
+				taskId = res.PreloadTaskId
+			}
+		} else {
+			request := dcdn.CreateRefreshDcdnObjectCachesRequest()
+			request.ObjectPath = strings.Join(chunk, "\n")
+			request.ObjectType = objectType
+
+			var res *dcdn.RefreshDcdnObjectCachesResponse
+			res, err = conn.RefreshDcdnObjectCaches(request)
+			if err == nil {
+				taskId = res.RefreshTaskId
+			}
+		}
+		if err != nil {
+			if IsExpectedErrors(err, dcdnCacheQuotaErrors) {
+				return diag.Errorf("daily DCDN %s quota exhausted after submitting tasks %s: %s", purgeType, strings.Join(taskIds, ", "), err)
+			}
+			return diag.FromErr(err)
+		}
+		taskIds = append(taskIds, strings.Split(taskId, ",")...)
+	}
+
+	d.SetId(strings.Join(taskIds, ","))
+	if err := d.Set("task_ids", taskIds); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.Get("wait_for_completion").(bool) {
+		if err := waitForDcdnRefreshTasks(ctx, conn, taskIds, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceAliyunDcdnCachePurgeRead(ctx, d, m)
+}
+
+// A purge is a one-off task, DCDN only keeps its history for a few days so
+// there is nothing to refresh from the API.
+func resourceAliyunDcdnCachePurgeRead(_ context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	return diags
+}
+
+// checkDcdnCacheQuota fails early when the remaining daily quota cannot cover
+// count paths, rather than leaving a purge half submitted.
+func checkDcdnCacheQuota(conn *dcdn.Client, purgeType, objectType string, count int) error {
+	res, err := conn.DescribeDcdnRefreshQuota(dcdn.CreateDescribeDcdnRefreshQuotaRequest())
+	if err != nil {
+		return err
+	}
+
+	remain, quota, kind := res.UrlRemain, res.UrlQuota, "URL refresh"
+	switch {
+	case purgeType == "preload":
+		remain, quota, kind = res.PreloadRemain, res.PreloadQuota, "preload"
+	case objectType == "Directory":
+		remain, quota, kind = res.DirRemain, res.DirQuota, "directory refresh"
+	case objectType == "Regex":
+		remain, quota, kind = res.RegexRemain, res.RegexQuota, "regex refresh"
+	}
+
+	n, err := strconv.Atoi(remain)
+	if err != nil {
+		return nil
+	}
+	if n < count {
+		return fmt.Errorf("daily DCDN %s quota exhausted: %d of %s remaining today, %d requested", kind, n, quota, count)
+	}
+
+	return nil
+}
+
+func waitForDcdnRefreshTasks(ctx context.Context, conn *dcdn.Client, taskIds []string, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"Refreshing"},
+		Target:  []string{"Complete"},
+		Refresh: func() (interface{}, string, error) {
+			for _, taskId := range taskIds {
+				request := dcdn.CreateDescribeDcdnRefreshTasksRequest()
+				request.TaskId = taskId
+				request.PageSize = requests.NewInteger(100)
+
+				seen := 0
+				for page := 1; ; page++ {
+					request.PageNumber = requests.NewInteger(page)
+
+					res, err := conn.DescribeDcdnRefreshTasks(request)
+					if err != nil {
+						return nil, "", err
+					}
+					for _, task := range res.Tasks.Task {
+						if task.Status == "Failed" {
+							return task, "Failed", fmt.Errorf("DCDN task %s for %s failed: %s", task.TaskId, task.ObjectPath, task.Description)
+						}
+						if task.Status != "Complete" {
+							return task, "Refreshing", nil
+						}
+					}
+
+					seen += len(res.Tasks.Task)
+					if len(res.Tasks.Task) == 0 || int64(seen) >= res.TotalCount {
+						break
+					}
+				}
+				// Freshly submitted tasks take a moment to be listed.
+				if seen == 0 {
+					return taskId, "Refreshing", nil
+				}
+			}
+			return taskIds, "Complete", nil
+		},
+		Timeout:    timeout,
+		MinTimeout: 5 * time.Second,
+	}
+
+	_, err := stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		if e, ok := err.(*resource.TimeoutError); ok {
+			return fmt.Errorf("timeout waiting for DCDN tasks %s: current status is %s", strings.Join(taskIds, ", "), e.LastState)
+		}
+		return fmt.Errorf("error waiting for DCDN tasks %s: %s", strings.Join(taskIds, ", "), err)
+	}
+
+	return nil
+}
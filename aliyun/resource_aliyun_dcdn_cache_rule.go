@@ -0,0 +1,337 @@
+package aliyun
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var dcdnCacheRuleFunctionNames = map[string]string{
+	"path":      "fullpath_based_ttl_set",
+	"extension": "filetype_based_ttl_set",
+	"directory": "path_based_ttl_set",
+}
+
+const dcdnHashKeyFunctionName = "set_hashkey_args"
+
+func resourceAliyunDcdnCacheRule() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   resourceAliyunDcdnCacheRuleRead,
+		CreateContext: resourceAliyunDcdnCacheRuleCreate,
+		UpdateContext: resourceAliyunDcdnCacheRuleUpdate,
+		DeleteContext: resourceAliyunDcdnCacheRuleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceAliyunDcdnCacheRuleImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"domain_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"path", "extension", "directory"}, false),
+			},
+			"match": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotWhiteSpace,
+				},
+			},
+			"ttl": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntBetween(0, 94608000),
+			},
+			"weight": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntBetween(1, 99),
+			},
+			"ignore_query_string": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+		},
+	}
+}
+
+// Cache rules are imported by domain:type:match, match being the paths or
+// directories of the rule separated by commas, or its extensions as
+// configured, such as example.com:extension:jpg,png. The
+// domain:config_ids form the rule is stored under is accepted as well.
+// ignore_query_string is not imported.
+func resourceAliyunDcdnCacheRuleImport(_ context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	conn := m.(Client).dcdnconn
+
+	parts := strings.SplitN(d.Id(), COLON_SEPARATED, 3)
+	if len(parts) != 3 {
+		return []*schema.ResourceData{d}, nil
+	}
+	functionName, ok := dcdnCacheRuleFunctionNames[parts[1]]
+	if !ok {
+		return nil, fmt.Errorf("invalid cache rule type %s in %s, expected one of path, extension or directory", parts[1], d.Id())
+	}
+
+	configs, err := describeDcdnDomainConfigs(conn, parts[0], []string{functionName})
+	if err != nil {
+		return nil, err
+	}
+
+	keyArg, keys := "path", strings.Split(parts[2], ",")
+	if functionName == "filetype_based_ttl_set" {
+		keyArg, keys = "file_type", []string{parts[2]}
+	}
+
+	var configIds []string
+	claimed := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		configId := ""
+		for _, config := range configs {
+			if !claimed[config.ConfigId] && config.Args[keyArg] == key {
+				configId = config.ConfigId
+				break
+			}
+		}
+		if configId == "" {
+			return nil, fmt.Errorf("no dcdn config %s with %s %s found on %s", functionName, keyArg, key, parts[0])
+		}
+		claimed[configId] = true
+		configIds = append(configIds, configId)
+	}
+
+	d.SetId(fmt.Sprintf("%s%s%s", parts[0], COLON_SEPARATED, strings.Join(configIds, ",")))
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceAliyunDcdnCacheRuleDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	parts, err := ParseResourceId(d.Id(), 2)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = deleteDcdnDomainConfigs(conn, parts[0], strings.Split(parts[1], ","))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func resourceAliyunDcdnCacheRuleUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChanges("match", "ttl", "weight", "ignore_query_string") {
+		parts, err := ParseResourceId(d.Id(), 2)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := setDcdnCacheRule(ctx, d, m, strings.Split(parts[1], ","), d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceAliyunDcdnCacheRuleRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnCacheRuleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := setDcdnCacheRule(ctx, d, m, nil, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceAliyunDcdnCacheRuleRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnCacheRuleRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	parts, err := ParseResourceId(d.Id(), 2)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	functionNames := []string{dcdnHashKeyFunctionName}
+	for _, functionName := range dcdnCacheRuleFunctionNames {
+		functionNames = append(functionNames, functionName)
+	}
+
+	configs, err := describeDcdnDomainConfigs(conn, parts[0], functionNames)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	ruleType, ignoreQueryString := "", false
+	match := make([]string, 0)
+	ttl, weight := 0, 0
+
+	for _, id := range strings.Split(parts[1], ",") {
+		for _, config := range configs {
+			if config.ConfigId != id {
+				continue
+			}
+			args := config.Args
+			if config.FunctionName == dcdnHashKeyFunctionName {
+				ignoreQueryString = args["disable"] == "on"
+				continue
+			}
+			for t, functionName := range dcdnCacheRuleFunctionNames {
+				if functionName == config.FunctionName {
+					ruleType = t
+				}
+			}
+			if ruleType == "extension" {
+				match = append(match, strings.Split(args["file_type"], ",")...)
+			} else {
+				match = append(match, args["path"])
+			}
+			ttl, _ = strconv.Atoi(args["ttl"])
+			weight, _ = strconv.Atoi(args["weight"])
+		}
+	}
+
+	if ruleType == "" {
+		d.SetId("")
+		return diags
+	}
+
+	d.Set("domain_name", parts[0])
+	d.Set("type", ruleType)
+	if err := d.Set("match", match); err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("ttl", ttl)
+	d.Set("weight", weight)
+	d.Set("ignore_query_string", ignoreQueryString)
+
+	return diags
+}
+
+// setDcdnCacheRule applies the rule on top of the configs listed in ids,
+// updating them in place, adding the missing ones and deleting the leftovers,
+// and stores the resulting config ids in the resource id before waiting for
+// them to be deployed.
+//
+// set_hashkey_args applies to the whole domain, so ignore_query_string only
+// manages a hashkey config the rule added itself, and refuses to take over
+// one that already exists.
+func setDcdnCacheRule(ctx context.Context, d *schema.ResourceData, m interface{}, ids []string, timeout time.Duration) error {
+	conn := m.(Client).dcdnconn
+	domain := d.Get("domain_name").(string)
+	functionName := dcdnCacheRuleFunctionNames[d.Get("type").(string)]
+
+	configs, err := describeDcdnDomainConfigs(conn, domain, []string{functionName, dcdnHashKeyFunctionName})
+	if err != nil {
+		return err
+	}
+
+	var ruleIds []string
+	hashKeyId := ""
+	for _, id := range ids {
+		for _, config := range configs {
+			if config.ConfigId != id {
+				continue
+			}
+			if config.FunctionName == dcdnHashKeyFunctionName {
+				hashKeyId = id
+			} else {
+				ruleIds = append(ruleIds, id)
+			}
+		}
+	}
+
+	keyArg := "path"
+	var keys []string
+	for _, v := range d.Get("match").([]interface{}) {
+		keys = append(keys, v.(string))
+	}
+	if functionName == "filetype_based_ttl_set" {
+		keyArg = "file_type"
+		keys = []string{strings.Join(keys, ",")}
+	}
+
+	functions := make([]map[string]interface{}, 0, len(keys)+1)
+	for i, key := range keys {
+		configId := ""
+		if i < len(ruleIds) {
+			configId = ruleIds[i]
+		}
+		function, err := domainFunction(functionName, configId, map[string]string{
+			keyArg:   key,
+			"ttl":    strconv.Itoa(d.Get("ttl").(int)),
+			"weight": strconv.Itoa(d.Get("weight").(int)),
+		})
+		if err != nil {
+			return err
+		}
+		functions = append(functions, function)
+	}
+
+	var obsolete []string
+	if len(ruleIds) > len(keys) {
+		obsolete = append(obsolete, ruleIds[len(keys):]...)
+	}
+
+	if d.Get("ignore_query_string").(bool) {
+		if hashKeyId == "" {
+			for _, config := range configs {
+				if config.FunctionName == dcdnHashKeyFunctionName {
+					return fmt.Errorf("dcdn config %s (%s) of %s is not managed by this cache rule, manage it with aliyun_dcdn_domain_config instead of ignore_query_string", dcdnHashKeyFunctionName, config.ConfigId, domain)
+				}
+			}
+		}
+		function, err := domainFunction(dcdnHashKeyFunctionName, hashKeyId, map[string]string{
+			"disable":       "on",
+			"hashkey_args":  "",
+			"keep_oss_args": "off",
+		})
+		if err != nil {
+			return err
+		}
+		functions = append(functions, function)
+	} else if hashKeyId != "" {
+		obsolete = append(obsolete, hashKeyId)
+	}
+
+	configIds, err := setDcdnDomainFunctions(ctx, conn, domain, functions, timeout)
+	if err != nil {
+		return err
+	}
+
+	// The configs are recorded as soon as they are written, leftovers
+	// included until they are deleted, so that a failure below leaves them
+	// in state rather than orphaned.
+	d.SetId(fmt.Sprintf("%s%s%s", domain, COLON_SEPARATED, strings.Join(append(configIds, obsolete...), ",")))
+
+	if err := deleteDcdnDomainConfigs(conn, domain, obsolete); err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s%s%s", domain, COLON_SEPARATED, strings.Join(configIds, ",")))
+
+	return waitForDcdnDomainConfigs(ctx, conn, domain, functions, configIds, timeout)
+}
@@ -2,13 +2,14 @@ package aliyun
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/cdn"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"strconv"
 	"time"
 )
 
@@ -18,10 +19,20 @@ func resourceAliyunDcdnDomain() *schema.Resource {
 		CreateContext: resourceAliyunDcdnDomainCreate,
 		UpdateContext: resourceAliyunDcdnDomainUpdate,
 		DeleteContext: resourceAliyunDcdnDomainDelete,
+		CustomizeDiff: customizeDomainSourcesDiff,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceAliyunDcdnDomainV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceAliyunDcdnDomainStateUpgradeV0,
+			},
+		},
+
 		Schema: map[string]*schema.Schema{
 			"resource_group_id": {
 				Type:     schema.TypeString,
@@ -43,37 +54,21 @@ func resourceAliyunDcdnDomain() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
-			"sources": {
-				Type:     schema.TypeSet,
-				Required: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"content": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"port": {
-							Type:         schema.TypeInt,
-							Optional:     true,
-							Default:      80,
-							ValidateFunc: validation.IntInSlice([]int{443, 80}),
-						},
-						"priority": {
-							Type:     schema.TypeString,
-							Optional: true,
-							Default:  "20",
-						},
-						"type": {
-							Type:         schema.TypeString,
-							Required:     true,
-							ValidateFunc: validation.StringInSlice([]string{"ipaddr", "domain", "oss"}, false),
-						},
-						"weight": {
-							Type:     schema.TypeString,
-							Optional: true,
-							Default:  "10",
-						},
-					},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"sources": domainSourcesSchema(),
+			// Takes over an existing CDN domain, whose aliyun_cdn_domain must
+			// have had migrate_to_dcdn applied before it is removed. It only
+			// matters on create, so changes are ignored once the domain exists.
+			"migrate_from_cdn": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return d.Id() != ""
 				},
 			},
 		},
@@ -86,7 +81,7 @@ func resourceAliyunDcdnDomain() *schema.Resource {
 	}
 }
 
-func resourceAliyunDcdnDomainDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceAliyunDcdnDomainDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 	conn := m.(Client).dcdnconn
 
@@ -94,6 +89,15 @@ func resourceAliyunDcdnDomainDelete(_ context.Context, d *schema.ResourceData, m
 	request.DomainName = d.Id()
 
 	_, err := conn.DeleteDcdnDomain(request)
+	if err != nil {
+		if IsExpectedErrors(err, []string{"InvalidDomain.NotFound"}) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	err = waitForDcdnDomainStatus(ctx, conn, d.Id(), domainDeletePendingStatus, []string{}, d.Timeout(schema.TimeoutDelete))
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -106,6 +110,15 @@ func resourceAliyunDcdnDomainDelete(_ context.Context, d *schema.ResourceData, m
 func resourceAliyunDcdnDomainUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	conn := m.(Client).dcdnconn
 
+	o, _ := d.GetChange("enabled")
+	enabled := o.(bool)
+	if d.HasChange("enabled") && d.Get("enabled").(bool) {
+		if err := setDcdnDomainEnabled(ctx, conn, d.Id(), true, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.FromErr(err)
+		}
+		enabled = true
+	}
+
 	if d.HasChange("scope") {
 		request := dcdn.CreateModifyDCdnDomainSchdmByPropertyRequest()
 		request.DomainName = d.Id()
@@ -137,6 +150,17 @@ func resourceAliyunDcdnDomainUpdate(ctx context.Context, d *schema.ResourceData,
 		if err != nil {
 			return diag.FromErr(err)
 		}
+
+		err = waitForDcdnDomainStatus(ctx, conn, d.Id(), domainPendingStatus, []string{domainTargetStatus(enabled)}, d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("enabled") && !d.Get("enabled").(bool) {
+		if err := setDcdnDomainEnabled(ctx, conn, d.Id(), false, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	return resourceAliyunDcdnDomainRead(ctx, d, m)
@@ -154,6 +178,24 @@ func resourceAliyunDcdnDomainCreate(ctx context.Context, d *schema.ResourceData,
 		request.ResourceGroupId = v.(string)
 	}
 
+	// Adding a domain served by CDN to DCDN moves it over along with its
+	// configs, so the CDN domain is only required to exist.
+	if d.Get("migrate_from_cdn").(bool) {
+		cdnRequest := cdn.CreateDescribeCdnDomainDetailRequest()
+		cdnRequest.DomainName = domain
+
+		res, err := m.(Client).cdnconn.DescribeCdnDomainDetail(cdnRequest)
+		if err != nil {
+			if IsExpectedErrors(err, []string{"InvalidDomain.NotFound"}) {
+				return diag.Errorf("cdn domain %s to migrate from is not found", domain)
+			}
+			return diag.FromErr(err)
+		}
+		if request.ResourceGroupId == "" {
+			request.ResourceGroupId = res.GetDomainDetailModel.ResourceGroupId
+		}
+	}
+
 	if v, ok := d.GetOk("scope"); ok {
 		request.Scope = v.(string)
 	}
@@ -169,31 +211,18 @@ func resourceAliyunDcdnDomainCreate(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 
-	err = resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		request := dcdn.CreateDescribeDcdnDomainDetailRequest()
-		request.DomainName = domain
-
-		res, err := conn.DescribeDcdnDomainDetail(request)
-
-		if err != nil {
-			return resource.NonRetryableError(fmt.Errorf("error creating dcdn: %s", err))
-		}
-
-		if res.DomainDetail.DomainStatus != "online" {
-			return resource.RetryableError(fmt.Errorf("dcdn creation is processing"))
-		}
-
-		if res.DomainDetail.DomainStatus == "online" {
-			return nil
-		}
+	d.SetId(domain)
 
-		return resource.NonRetryableError(fmt.Errorf("error creating dcdn: unkown state"))
-	})
+	err = waitForDcdnDomainStatus(ctx, conn, domain, domainPendingStatus, []string{"online"}, d.Timeout(schema.TimeoutCreate))
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	d.SetId(domain)
+	if !d.Get("enabled").(bool) {
+		if err := setDcdnDomainEnabled(ctx, conn, domain, false, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 
 	return resourceAliyunDcdnDomainRead(ctx, d, m)
 }
@@ -207,46 +236,141 @@ func resourceAliyunDcdnDomainRead(_ context.Context, d *schema.ResourceData, m i
 
 	res, err := conn.DescribeDcdnDomainDetail(request)
 	if err != nil {
+		if IsExpectedErrors(err, []string{"InvalidDomain.NotFound"}) {
+			d.SetId("")
+			return nil
+		}
 		return diag.FromErr(err)
 	}
 
 	d.Set("domain_name", d.Id())
 	d.Set("resource_group_id", res.DomainDetail.ResourceGroupId)
 	d.Set("scope", res.DomainDetail.Scope)
-	sources := make([]map[string]interface{}, 0)
-	for _, val := range res.DomainDetail.Sources.Source {
-		sources = append(sources, map[string]interface{}{
-			"content":  val.Content,
-			"port":     val.Port,
-			"priority": val.Priority,
-			"type":     val.Type,
-			"weight":   val.Weight,
-		})
-	}
-	if err := d.Set("sources", sources); err != nil {
+	if err := d.Set("sources", flattenDcdnSources(res.DomainDetail.Sources.Source)); err != nil {
 		return diag.FromErr(err)
 	}
-	d.Set("sources", sources)
 	d.Set("cname", res.DomainDetail.Cname)
+	d.Set("enabled", res.DomainDetail.DomainStatus != "offline")
 
 	return diags
 }
 
-func convertSourcesToString(v []interface{}) (string, error) {
-	arrayMaps := make([]interface{}, len(v))
-	for i, vv := range v {
-		item := vv.(map[string]interface{})
-		arrayMaps[i] = map[string]interface{}{
-			"Content":  item["content"],
-			"Port":     item["port"],
-			"Priority": item["priority"],
-			"Type":     item["type"],
-			"Weight":   item["weight"],
+func setDcdnDomainEnabled(ctx context.Context, conn *dcdn.Client, domain string, enabled bool, timeout time.Duration) error {
+	if enabled {
+		request := dcdn.CreateStartDcdnDomainRequest()
+		request.DomainName = domain
+		if _, err := conn.StartDcdnDomain(request); err != nil {
+			return err
 		}
+		return waitForDcdnDomainStatus(ctx, conn, domain, domainStartPendingStatus, []string{"online"}, timeout)
 	}
-	maps, err := json.Marshal(arrayMaps)
-	if err != nil {
-		return "", err
+
+	request := dcdn.CreateStopDcdnDomainRequest()
+	request.DomainName = domain
+	if _, err := conn.StopDcdnDomain(request); err != nil {
+		return err
+	}
+	return waitForDcdnDomainStatus(ctx, conn, domain, domainStopPendingStatus, []string{"offline"}, timeout)
+}
+
+func dcdnDomainStateRefreshFunc(conn *dcdn.Client, domain string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		request := dcdn.CreateDescribeDcdnDomainDetailRequest()
+		request.DomainName = domain
+
+		res, err := conn.DescribeDcdnDomainDetail(request)
+		if err != nil {
+			if IsExpectedErrors(err, []string{"InvalidDomain.NotFound"}) {
+				return nil, "", nil
+			}
+			return nil, "", err
+		}
+
+		return res, res.DomainDetail.DomainStatus, nil
+	}
+}
+
+func waitForDcdnDomainStatus(ctx context.Context, conn *dcdn.Client, domain string, pending, target []string, timeout time.Duration) error {
+	return waitForDomainStatus(ctx, "dcdn", domain, dcdnDomainStateRefreshFunc(conn, domain), pending, target, timeout)
+}
+
+func resourceAliyunDcdnDomainV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"resource_group_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"domain_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"scope": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"domestic", "global", "overseas"}, false),
+				Default:      "domestic",
+			},
+			"cname": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"sources": {
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"content": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"port": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      80,
+							ValidateFunc: validation.IntInSlice([]int{443, 80}),
+						},
+						"priority": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "20",
+						},
+						"type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{"ipaddr", "domain", "oss"}, false),
+						},
+						"weight": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "10",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// resourceAliyunDcdnDomainStateUpgradeV0 converts the priority and weight of
+// sources from the strings sent to the API to a priority name and an integer.
+func resourceAliyunDcdnDomainStateUpgradeV0(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	sources, _ := rawState["sources"].([]interface{})
+	for _, v := range sources {
+		source, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if priority, ok := source["priority"].(string); ok {
+			source["priority"] = domainSourcePriority(priority)
+		}
+		weight, err := strconv.Atoi(fmt.Sprint(source["weight"]))
+		if err != nil {
+			weight = 10
+		}
+		source["weight"] = weight
 	}
-	return string(maps), nil
+	return rawState, nil
 }
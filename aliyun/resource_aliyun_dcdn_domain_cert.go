@@ -2,16 +2,31 @@ package aliyun
 
 import (
 	"context"
+	"fmt"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/responses"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceAliyunDcdnDomainCert() *schema.Resource {
 	return &schema.Resource{
 		ReadContext:   resourceAliyunDcdnDomainCertRead,
 		CreateContext: resourceAliyunDcdnDomainCertCreate,
+		UpdateContext: resourceAliyunDcdnDomainCertUpdate,
 		DeleteContext: resourceAliyunDcdnDomainCertDelete,
+		CustomizeDiff: resourceAliyunDcdnDomainCertCustomizeDiff,
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceAliyunDcdnDomainCertV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceAliyunDcdnDomainCertStateUpgradeV0,
+			},
+		},
 
 		Schema: map[string]*schema.Schema{
 			"domain_name": {
@@ -21,23 +36,101 @@ func resourceAliyunDcdnDomainCert() *schema.Resource {
 			},
 			"cert_name": {
 				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Optional: true,
+				Computed: true,
+			},
+			"cert_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "upload",
+				ValidateFunc: validation.StringInSlice([]string{"upload", "cas"}, false),
 			},
 			"ssl_pub": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:             schema.TypeString,
+				Optional:         true,
+				ConflictsWith:    []string{"cert_id"},
+				ValidateDiagFunc: validateCertificateChainPem,
 			},
 			"ssl_pri": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				ConflictsWith:    []string{"cert_id", "ssl_pri_file", "ssl_pri_env"},
+				ValidateDiagFunc: validatePrivateKeyPem,
+				StateFunc:        sha256Hex,
+			},
+			"ssl_pri_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"cert_id", "ssl_pri", "ssl_pri_env"},
+			},
+			"ssl_pri_env": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"cert_id", "ssl_pri", "ssl_pri_file"},
+			},
+			"ssl_pri_sha256": {
 				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Computed: true,
+			},
+			"cert_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"cert_region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  string(Hangzhou),
+			},
+			"not_after": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"issuer": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"subject_alt_names": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"renew_before_days": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"ready_for_renewal": {
+				Type:     schema.TypeBool,
+				Computed: true,
 			},
 		},
 	}
 }
 
+func resourceAliyunDcdnDomainCertCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if err := customizeDomainCertDiff(d); err != nil {
+		return err
+	}
+
+	if d.GetRawConfig().IsNull() {
+		return nil
+	}
+
+	if d.Get("cert_type").(string) == "cas" {
+		if d.NewValueKnown("cert_id") && d.Get("cert_id").(string) == "" && d.NewValueKnown("cert_name") && d.Get("cert_name").(string) == "" {
+			return fmt.Errorf("cert_id or cert_name is required when cert_type is cas")
+		}
+	}
+
+	return nil
+}
+
 func resourceAliyunDcdnDomainCertDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 	conn := m.(Client).dcdnconn
@@ -56,25 +149,28 @@ func resourceAliyunDcdnDomainCertDelete(_ context.Context, d *schema.ResourceDat
 	return diags
 }
 
-func resourceAliyunDcdnDomainCertCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	conn := m.(Client).dcdnconn
-	domain := d.Get("domain_name").(string)
+func resourceAliyunDcdnDomainCertUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChanges("cert_name", "cert_type", "ssl_pub", "ssl_pri_sha256", "cert_id", "cert_region") {
+		if diags := checkDomainCertificate(d); diags.HasError() {
+			return diags
+		}
+		if err := setDcdnDomainCertificate(d, m); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 
-	request := dcdn.CreateSetDcdnDomainCertificateRequest()
-	request.DomainName = domain
-	request.CertName = d.Get("cert_name").(string)
-	request.CertType = "upload"
-	request.ForceSet = "1"
-	request.SSLProtocol = "on"
-	request.SSLPub = d.Get("ssl_pub").(string)
-	request.SSLPri = d.Get("ssl_pri").(string)
+	return resourceAliyunDcdnDomainCertRead(ctx, d, m)
+}
 
-	_, err := conn.SetDcdnDomainCertificate(request)
-	if err != nil {
+func resourceAliyunDcdnDomainCertCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if diags := checkDomainCertificate(d); diags.HasError() {
+		return diags
+	}
+	if err := setDcdnDomainCertificate(d, m); err != nil {
 		return diag.FromErr(err)
 	}
 
-	d.SetId(domain)
+	d.SetId(d.Get("domain_name").(string))
 
 	return resourceAliyunDcdnDomainCertRead(ctx, d, m)
 }
@@ -91,10 +187,94 @@ func resourceAliyunDcdnDomainCertRead(_ context.Context, d *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 
+	if len(res.CertInfos.CertInfo) == 0 || res.CertInfos.CertInfo[0].SSLProtocol == "off" {
+		d.SetId("")
+		return diags
+	}
+
 	certInfo := res.CertInfos.CertInfo[0]
 
 	d.Set("domain_name", d.Id())
 	d.Set("cert_name", certInfo.CertName)
+	if certInfo.CertType == "upload" || certInfo.CertType == "cas" {
+		d.Set("cert_type", certInfo.CertType)
+	}
 
-	return diags
+	return append(diags, setDomainCertificateDetails(d, "DCDN", certInfo.Issuer, certInfo.SSLPub, certInfo.CertExpireTime)...)
+}
+
+// SetDcdnDomainCertificate of the SDK version in use lacks CertId, which
+// selects a CAS certificate by id instead of by name, so its request is
+// declared here the way the SDK generates it.
+type dcdnSetDomainCertificateRequest struct {
+	*requests.RpcRequest
+	DomainName  string `position:"Query" name:"DomainName"`
+	CertName    string `position:"Query" name:"CertName"`
+	CertType    string `position:"Query" name:"CertType"`
+	CertId      string `position:"Query" name:"CertId"`
+	Region      string `position:"Query" name:"Region"`
+	SSLProtocol string `position:"Query" name:"SSLProtocol"`
+	SSLPub      string `position:"Query" name:"SSLPub"`
+	SSLPri      string `position:"Query" name:"SSLPri"`
+	ForceSet    string `position:"Query" name:"ForceSet"`
+}
+
+// setDcdnDomainCertificate deploys the configured certificate with ForceSet,
+// replacing whatever certificate the domain serves without turning HTTPS off.
+func setDcdnDomainCertificate(d *schema.ResourceData, m interface{}) error {
+	conn := m.(Client).dcdnconn
+
+	request := &dcdnSetDomainCertificateRequest{RpcRequest: &requests.RpcRequest{}}
+	request.InitWithApiInfo("dcdn", "2018-01-15", "SetDcdnDomainCertificate", "", "")
+	request.Method = requests.POST
+	request.DomainName = d.Get("domain_name").(string)
+	request.CertName = d.Get("cert_name").(string)
+	request.CertType = d.Get("cert_type").(string)
+	request.ForceSet = "1"
+	request.SSLProtocol = "on"
+
+	if request.CertType == "cas" {
+		request.Region = d.Get("cert_region").(string)
+		request.CertId = d.Get("cert_id").(string)
+	} else {
+		key, _, err := domainCertPrivateKey(d)
+		if err != nil {
+			return err
+		}
+		request.SSLPub = d.Get("ssl_pub").(string)
+		request.SSLPri = key
+	}
+
+	return conn.DoAction(request, &responses.BaseResponse{})
+}
+
+func resourceAliyunDcdnDomainCertV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"domain_name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"cert_name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"ssl_pub": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"ssl_pri": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+	}
+}
+
+func resourceAliyunDcdnDomainCertStateUpgradeV0(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	if v, ok := rawState["ssl_pri"].(string); ok && v != "" {
+		rawState["ssl_pri"] = sha256Hex(v)
+		rawState["ssl_pri_sha256"] = sha256Hex(v)
+	}
+	return rawState, nil
 }
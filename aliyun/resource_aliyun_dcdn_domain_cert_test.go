@@ -0,0 +1,51 @@
+package aliyun
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestResourceAliyunDcdnDomainCertStateUpgradeV0(t *testing.T) {
+	cases := []struct {
+		name  string
+		state map[string]interface{}
+		want  map[string]interface{}
+	}{
+		{
+			name: "private key",
+			state: map[string]interface{}{
+				"domain_name": "example.com",
+				"ssl_pri":     "key",
+			},
+			want: map[string]interface{}{
+				"domain_name":    "example.com",
+				"ssl_pri":        sha256Hex("key"),
+				"ssl_pri_sha256": sha256Hex("key"),
+			},
+		},
+		{
+			name: "no private key",
+			state: map[string]interface{}{
+				"domain_name": "example.com",
+				"ssl_pri":     "",
+			},
+			want: map[string]interface{}{
+				"domain_name": "example.com",
+				"ssl_pri":     "",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := resourceAliyunDcdnDomainCertStateUpgradeV0(context.Background(), c.state, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("upgraded state = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
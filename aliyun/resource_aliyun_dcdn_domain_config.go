@@ -2,19 +2,21 @@ package aliyun
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"time"
 )
 
 func resourceAliyunDcdnDomainConfig() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceAliyunDcdnDomainConfigCreate,
 		ReadContext:   resourceAliyunDcdnDomainConfigRead,
+		UpdateContext: resourceAliyunDcdnDomainConfigUpdate,
 		DeleteContext: resourceAliyunDcdnDomainConfigDelete,
+		CustomizeDiff: customizeDomainConfigArgsDiff,
 
 		Schema: map[string]*schema.Schema{
 			"domain_name": {
@@ -31,22 +33,32 @@ func resourceAliyunDcdnDomainConfig() *schema.Resource {
 			"function_args": {
 				Type:     schema.TypeSet,
 				Required: true,
-				ForceNew: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"arg_name": {
 							Type:     schema.TypeString,
 							Required: true,
-							ForceNew: true,
 						},
 						"arg_value": {
 							Type:     schema.TypeString,
 							Required: true,
-							ForceNew: true,
 						},
 					},
 				},
 			},
+			"config_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
 		},
 	}
 }
@@ -55,23 +67,23 @@ func resourceAliyunDcdnDomainConfigDelete(_ context.Context, d *schema.ResourceD
 	var diags diag.Diagnostics
 	conn := m.(Client).dcdnconn
 
-	parts, err := ParseResourceId(d.Id(), 2)
+	domain, functionName, configId, err := parseDomainConfigId(d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	request := dcdn.CreateDescribeDcdnDomainConfigsRequest()
-	request.DomainName = parts[0]
-	request.FunctionNames = parts[1]
-
-	res, err := conn.DescribeDcdnDomainConfigs(request)
+	configs, err := describeDcdnDomainConfigs(conn, domain, []string{functionName})
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	config := res.DomainConfigs.DomainConfig[0]
+	config, ok := findDomainConfig(configs, configId)
+	if !ok {
+		d.SetId("")
+		return diags
+	}
 
 	deleteRequest := dcdn.CreateDeleteDcdnSpecificConfigRequest()
 	deleteRequest.ConfigId = config.ConfigId
-	deleteRequest.DomainName = parts[0]
+	deleteRequest.DomainName = domain
 
 	_, err = conn.DeleteDcdnSpecificConfig(deleteRequest)
 	if err != nil {
@@ -87,68 +99,123 @@ func resourceAliyunDcdnDomainConfigRead(_ context.Context, d *schema.ResourceDat
 	var diags diag.Diagnostics
 	conn := m.(Client).dcdnconn
 
-	parts, err := ParseResourceId(d.Id(), 2)
+	domain, functionName, configId, err := parseDomainConfigId(d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	request := dcdn.CreateDescribeDcdnDomainConfigsRequest()
-	request.DomainName = parts[0]
-	request.FunctionNames = parts[1]
-
-	res, err := conn.DescribeDcdnDomainConfigs(request)
+	configs, err := describeDcdnDomainConfigs(conn, domain, []string{functionName})
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	config := res.DomainConfigs.DomainConfig[0]
+	config, ok := findDomainConfig(configs, configId)
+	if !ok {
+		d.SetId("")
+		return diags
+	}
 
 	var funArgs []map[string]string
 
-	for _, args := range config.FunctionArgs.FunctionArg {
-		if args.ArgName == "cert" || args.ArgName == "cert_id" || args.ArgName == "cert_name" || args.ArgName == "cert_type" || args.ArgName == "dkey" || args.ArgName == "pkey" || args.ArgName == "https" {
+	for name, value := range config.Args {
+		if name == "cert" || name == "cert_id" || name == "cert_name" || name == "cert_type" || name == "dkey" || name == "pkey" || name == "https" {
 			continue
 		}
 		funArgs = append(funArgs, map[string]string{
-			"arg_name":  args.ArgName,
-			"arg_value": args.ArgValue,
+			"arg_name":  name,
+			"arg_value": value,
 		})
 	}
 
-	d.Set("domain_name", parts[0])
-	d.Set("function_name", parts[1])
+	d.SetId(fmt.Sprintf("%s%s%s%s%s", domain, COLON_SEPARATED, functionName, COLON_SEPARATED, config.ConfigId))
+	d.Set("domain_name", domain)
+	d.Set("function_name", functionName)
 	d.Set("function_args", funArgs)
+	d.Set("config_id", config.ConfigId)
+	d.Set("status", config.Status)
 
 	return diags
 }
 
-func resourceAliyunDcdnDomainConfigCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func resourceAliyunDcdnDomainConfigUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	conn := m.(Client).dcdnconn
 
-	config := make([]map[string]interface{}, 1)
-	functionArgs := d.Get("function_args").(*schema.Set).List()
-	args := make([]map[string]interface{}, len(functionArgs))
-	for key, value := range functionArgs {
-		arg := value.(map[string]interface{})
-		args[key] = map[string]interface{}{
-			"argName":  arg["arg_name"],
-			"argValue": arg["arg_value"],
+	if d.HasChange("function_args") {
+		domain, functionName, configId, err := parseDomainConfigId(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		function, err := domainFunction(functionName, configId, convertFunctionArgsToMap(d.Get("function_args").(*schema.Set).List()))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		functions := []map[string]interface{}{function}
+
+		configIds, err := setDcdnDomainFunctions(ctx, conn, domain, functions, d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		err = waitForDcdnDomainConfigs(ctx, conn, domain, functions, configIds, d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			return diag.FromErr(err)
 		}
 	}
-	config[0] = map[string]interface{}{
-		"functionArgs": args,
-		"functionName": d.Get("function_name").(string),
-	}
-	functions, _ := json.Marshal(config)
 
-	request := dcdn.CreateBatchSetDcdnDomainConfigsRequest()
-	request.DomainNames = d.Get("domain_name").(string)
-	request.Functions = string(functions)
+	return resourceAliyunDcdnDomainConfigRead(ctx, d, m)
+}
 
-	_, err := conn.BatchSetDcdnDomainConfigs(request)
+func resourceAliyunDcdnDomainConfigCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn := m.(Client).dcdnconn
+
+	domain := d.Get("domain_name").(string)
+	functionName := d.Get("function_name").(string)
+
+	function, err := domainFunction(functionName, "", convertFunctionArgsToMap(d.Get("function_args").(*schema.Set).List()))
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	functions := []map[string]interface{}{function}
 
-	d.SetId(fmt.Sprintf("%s:%s", request.DomainNames, d.Get("function_name").(string)))
+	configIds, err := setDcdnDomainFunctions(ctx, conn, domain, functions, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s%s%s%s%s", domain, COLON_SEPARATED, functionName, COLON_SEPARATED, configIds[0]))
+	d.Set("config_id", configIds[0])
+
+	err = waitForDcdnDomainConfigs(ctx, conn, domain, functions, configIds, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	return resourceAliyunDcdnDomainConfigRead(ctx, d, m)
 }
+
+// customizeDomainConfigArgsDiff rejects function_args naming the same
+// argument twice, which a config can only hold once.
+func customizeDomainConfigArgsDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("function_args") {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for _, v := range d.Get("function_args").(*schema.Set).List() {
+		name := v.(map[string]interface{})["arg_name"].(string)
+		if seen[name] {
+			return fmt.Errorf("function_args has more than one arg_name %s", name)
+		}
+		seen[name] = true
+	}
+
+	return nil
+}
+
+func convertFunctionArgsToMap(v []interface{}) map[string]string {
+	args := make(map[string]string, len(v))
+	for _, vv := range v {
+		arg := vv.(map[string]interface{})
+		args[arg["arg_name"].(string)] = arg["arg_value"].(string)
+	}
+	return args
+}
@@ -0,0 +1,275 @@
+package aliyun
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"strconv"
+	"time"
+)
+
+var dcdnHttpsFunctionNames = []string{"https_force", "https_option", "https_tls_version", "HSTS"}
+
+var dcdnTlsVersionArgs = map[string]string{
+	"TLSv1.0": "tls10",
+	"TLSv1.1": "tls11",
+	"TLSv1.2": "tls12",
+	"TLSv1.3": "tls13",
+}
+
+func resourceAliyunDcdnDomainHttps() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   resourceAliyunDcdnDomainHttpsRead,
+		CreateContext: resourceAliyunDcdnDomainHttpsCreate,
+		UpdateContext: resourceAliyunDcdnDomainHttpsUpdate,
+		DeleteContext: resourceAliyunDcdnDomainHttpsDelete,
+		CustomizeDiff: resourceAliyunDcdnDomainHttpsCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: importDcdnDomainConfigs(dcdnHttpsFunctionNames),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"domain_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"force_redirect": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"http2": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"tls_versions": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice([]string{"TLSv1.0", "TLSv1.1", "TLSv1.2", "TLSv1.3"}, false),
+				},
+			},
+			"hsts": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"max_age": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntBetween(0, 63072000),
+						},
+						"include_subdomains": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+					},
+				},
+			},
+			"ocsp_stapling": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"config_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+		},
+	}
+}
+
+func resourceAliyunDcdnDomainHttpsCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if len(d.Get("hsts").([]interface{})) > 0 && !d.Get("force_redirect").(bool) {
+		return fmt.Errorf("hsts requires force_redirect to be enabled")
+	}
+
+	if d.Get("http2").(bool) && d.NewValueKnown("tls_versions") {
+		versions := d.Get("tls_versions").(*schema.Set)
+		if versions.Len() > 0 && !versions.Contains("TLSv1.2") && !versions.Contains("TLSv1.3") {
+			return fmt.Errorf("http2 requires TLSv1.2 or TLSv1.3 in tls_versions")
+		}
+	}
+
+	return nil
+}
+
+func resourceAliyunDcdnDomainHttpsDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	err := deleteDcdnDomainConfigs(conn, d.Id(), expandStringList(d.Get("config_ids").([]interface{})))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func resourceAliyunDcdnDomainHttpsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChanges("force_redirect", "http2", "tls_versions", "hsts", "ocsp_stapling") {
+		if err := setDcdnDomainHttps(ctx, d, m, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceAliyunDcdnDomainHttpsRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnDomainHttpsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	domain := d.Get("domain_name").(string)
+
+	d.SetId(domain)
+
+	if err := setDcdnDomainHttps(ctx, d, m, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceAliyunDcdnDomainHttpsRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnDomainHttpsRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	configs, err := describeTrackedDcdnDomainConfigs(conn, d, dcdnHttpsFunctionNames)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	forceRedirect, http2, ocspStapling := false, false, false
+	tlsVersions := make([]string, 0)
+	hsts := make([]map[string]interface{}, 0)
+	configIds := make([]string, 0, len(configs))
+
+	for _, config := range configs {
+		configIds = append(configIds, config.ConfigId)
+		args := config.Args
+		switch config.FunctionName {
+		case "https_force":
+			forceRedirect = args["enable"] == "on"
+		case "https_option":
+			http2 = args["http2"] == "on"
+			ocspStapling = args["ocsp_stapling"] == "on"
+		case "https_tls_version":
+			for version, arg := range dcdnTlsVersionArgs {
+				if args[arg] == "on" {
+					tlsVersions = append(tlsVersions, version)
+				}
+			}
+		case "HSTS":
+			if args["enabled"] != "on" {
+				continue
+			}
+			maxAge, _ := strconv.Atoi(args["https_hsts_max_age"])
+			hsts = append(hsts, map[string]interface{}{
+				"max_age":            maxAge,
+				"include_subdomains": args["https_hsts_include_subdomains"] == "on",
+			})
+		}
+	}
+
+	d.Set("domain_name", d.Id())
+	d.Set("force_redirect", forceRedirect)
+	d.Set("http2", http2)
+	d.Set("ocsp_stapling", ocspStapling)
+	if err := d.Set("tls_versions", tlsVersions); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("hsts", hsts); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("config_ids", configIds); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+func setDcdnDomainHttps(ctx context.Context, d *schema.ResourceData, m interface{}, timeout time.Duration) error {
+	conn := m.(Client).dcdnconn
+	domain := d.Id()
+
+	configs, err := describeDcdnDomainConfigs(conn, domain, dcdnHttpsFunctionNames)
+	if err != nil {
+		return err
+	}
+	tracked := expandStringList(d.Get("config_ids").([]interface{}))
+	configIds := make(map[string]string, len(configs))
+	for _, config := range filterDomainConfigs(configs, tracked) {
+		configIds[config.FunctionName] = config.ConfigId
+	}
+
+	args := map[string]map[string]string{
+		"https_force": {
+			"enable": OnOff(d.Get("force_redirect").(bool)),
+		},
+		"https_option": {
+			"http2":         OnOff(d.Get("http2").(bool)),
+			"ocsp_stapling": OnOff(d.Get("ocsp_stapling").(bool)),
+		},
+		"HSTS": {
+			"enabled": "off",
+		},
+	}
+
+	if v, ok := d.GetOk("tls_versions"); ok {
+		versions := v.(*schema.Set)
+		args["https_tls_version"] = map[string]string{}
+		for version, arg := range dcdnTlsVersionArgs {
+			args["https_tls_version"][arg] = OnOff(versions.Contains(version))
+		}
+	}
+
+	if v, ok := d.GetOk("hsts"); ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
+		hsts := v.([]interface{})[0].(map[string]interface{})
+		args["HSTS"] = map[string]string{
+			"enabled":                       "on",
+			"https_hsts_max_age":            strconv.Itoa(hsts["max_age"].(int)),
+			"https_hsts_include_subdomains": OnOff(hsts["include_subdomains"].(bool)),
+		}
+	}
+
+	var added []string
+	var kept []string
+	functions := make([]map[string]interface{}, 0, len(args))
+	for _, functionName := range dcdnHttpsFunctionNames {
+		if _, ok := args[functionName]; !ok {
+			if configIds[functionName] != "" {
+				kept = append(kept, configIds[functionName])
+			}
+			continue
+		}
+		if configIds[functionName] == "" {
+			added = append(added, functionName)
+		}
+		function, err := domainFunction(functionName, configIds[functionName], args[functionName])
+		if err != nil {
+			return err
+		}
+		functions = append(functions, function)
+	}
+
+	if err := checkUntrackedDcdnDomainConfigs(domain, configs, tracked, added); err != nil {
+		return err
+	}
+
+	return setTrackedDcdnDomainConfigs(ctx, conn, d, functions, kept, nil, timeout)
+}
@@ -0,0 +1,87 @@
+package aliyun
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestResourceAliyunDcdnDomainStateUpgradeV0(t *testing.T) {
+	cases := []struct {
+		name    string
+		sources []interface{}
+		want    []interface{}
+	}{
+		{
+			name: "primary",
+			sources: []interface{}{
+				map[string]interface{}{"content": "192.0.2.1", "port": 80, "priority": "20", "type": "ipaddr", "weight": "10"},
+			},
+			want: []interface{}{
+				map[string]interface{}{"content": "192.0.2.1", "port": 80, "priority": "primary", "type": "ipaddr", "weight": 10},
+			},
+		},
+		{
+			name: "backup",
+			sources: []interface{}{
+				map[string]interface{}{"content": "example.com", "port": 443, "priority": "30", "type": "domain", "weight": "50"},
+			},
+			want: []interface{}{
+				map[string]interface{}{"content": "example.com", "port": 443, "priority": "backup", "type": "domain", "weight": 50},
+			},
+		},
+		{
+			name: "unknown priority and invalid weight",
+			sources: []interface{}{
+				map[string]interface{}{"content": "192.0.2.1", "port": 80, "priority": "99", "type": "ipaddr", "weight": ""},
+			},
+			want: []interface{}{
+				map[string]interface{}{"content": "192.0.2.1", "port": 80, "priority": "99", "type": "ipaddr", "weight": 10},
+			},
+		},
+		{
+			name:    "no sources",
+			sources: nil,
+			want:    nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			state := map[string]interface{}{"domain_name": "example.com"}
+			if c.sources != nil {
+				state["sources"] = c.sources
+			}
+
+			got, err := resourceAliyunDcdnDomainStateUpgradeV0(context.Background(), state, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if sources, _ := got["sources"].([]interface{}); !reflect.DeepEqual(sources, c.want) {
+				t.Errorf("upgraded sources = %v, want %v", sources, c.want)
+			}
+			if got["domain_name"] != "example.com" {
+				t.Errorf("domain_name is %v after the upgrade", got["domain_name"])
+			}
+		})
+	}
+}
+
+// The state upgrader decodes version 0 states with this schema, so it must
+// keep the attributes aliyun_dcdn_domain had then, and only those.
+func TestResourceAliyunDcdnDomainV0Attributes(t *testing.T) {
+	var got []string
+	for name := range resourceAliyunDcdnDomainV0().CoreConfigSchema().Attributes {
+		got = append(got, name)
+	}
+	for name := range resourceAliyunDcdnDomainV0().CoreConfigSchema().BlockTypes {
+		got = append(got, name)
+	}
+	sort.Strings(got)
+
+	want := []string{"cname", "domain_name", "id", "resource_group_id", "scope", "sources"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("version 0 attributes are %v, want %v", got, want)
+	}
+}
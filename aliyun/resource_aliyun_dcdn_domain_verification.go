@@ -0,0 +1,87 @@
+package aliyun
+
+import (
+	"context"
+	"fmt"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"time"
+)
+
+func resourceAliyunDcdnDomainVerification() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   resourceAliyunDcdnDomainVerificationRead,
+		CreateContext: resourceAliyunDcdnDomainVerificationCreate,
+		DeleteContext: resourceAliyunDcdnDomainVerificationDelete,
+
+		Schema: map[string]*schema.Schema{
+			"domain_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"verify_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "dnsCheck",
+				ValidateFunc: validation.StringInSlice([]string{"dnsCheck", "fileCheck"}, false),
+			},
+			"content": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+	}
+}
+
+func resourceAliyunDcdnDomainVerificationDelete(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
+
+func resourceAliyunDcdnDomainVerificationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn := m.(Client).dcdnconn
+	domain := d.Get("domain_name").(string)
+
+	request := dcdn.CreateVerifyDcdnDomainOwnerRequest()
+	request.DomainName = domain
+	request.VerifyType = d.Get("verify_type").(string)
+
+	var content string
+	err := resource.RetryContext(ctx, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		res, err := conn.VerifyDcdnDomainOwner(request)
+		if err != nil {
+			if IsExpectedErrors(err, []string{"DomainOwnerVerifyFail"}) {
+				return resource.RetryableError(fmt.Errorf("dcdn domain %s ownership is not verified yet: %s", domain, err))
+			}
+			return resource.NonRetryableError(err)
+		}
+
+		content = res.Content
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(domain)
+	d.Set("content", content)
+
+	return resourceAliyunDcdnDomainVerificationRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnDomainVerificationRead(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	d.Set("domain_name", d.Id())
+
+	return diags
+}
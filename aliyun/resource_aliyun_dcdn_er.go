@@ -0,0 +1,178 @@
+package aliyun
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+)
+
+func resourceAliyunDcdnEr() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   resourceAliyunDcdnErRead,
+		CreateContext: resourceAliyunDcdnErCreate,
+		UpdateContext: resourceAliyunDcdnErUpdate,
+		DeleteContext: resourceAliyunDcdnErDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"environment": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice([]string{"staging", "production"}, false),
+						},
+						"spec_name": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "5ms",
+						},
+						"allowed_hosts": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceAliyunDcdnErDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	request := dcdn.CreateDeleteRoutineRequest()
+	request.Name = d.Id()
+
+	_, err := conn.DeleteRoutine(request)
+	if err != nil && !IsExpectedErrors(err, dcdnRoutineNotFoundErrors) {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func resourceAliyunDcdnErUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn := m.(Client).dcdnconn
+
+	if d.HasChanges("description", "environment") {
+		envConf, err := dcdnRoutineEnvConf(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		request := dcdn.CreateEditRoutineConfRequest()
+		request.Name = d.Id()
+		request.Description = d.Get("description").(string)
+		request.EnvConf = envConf
+
+		if _, err := conn.EditRoutineConf(request); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceAliyunDcdnErRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnErCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn := m.(Client).dcdnconn
+
+	envConf, err := dcdnRoutineEnvConf(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	request := dcdn.CreateCreateRoutineRequest()
+	request.Name = d.Get("name").(string)
+	request.Description = d.Get("description").(string)
+	request.EnvConf = envConf
+
+	if _, err := conn.CreateRoutine(request); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(request.Name)
+
+	return resourceAliyunDcdnErRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnErRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	routine, err := describeDcdnRoutine(conn, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if routine == nil {
+		log.Printf("[WARN] DCDN routine %s not found, removing from state", d.Id())
+		d.SetId("")
+		return diags
+	}
+
+	environments := make([]map[string]interface{}, 0)
+	envs, _ := routine["Envs"].([]interface{})
+	for _, v := range envs {
+		env, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		allowedHosts, _ := env["AllowedHosts"].([]interface{})
+		environments = append(environments, map[string]interface{}{
+			"name":          dcdnContentString(env["Env"]),
+			"spec_name":     dcdnContentString(env["SpecName"]),
+			"allowed_hosts": allowedHosts,
+		})
+	}
+
+	d.Set("name", d.Id())
+	d.Set("description", dcdnContentString(routine["Description"]))
+	if err := d.Set("environment", environments); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+// dcdnRoutineEnvConf builds the EnvConf argument of CreateRoutine and
+// EditRoutineConf, keyed by environment name.
+func dcdnRoutineEnvConf(d *schema.ResourceData) (string, error) {
+	envConf := make(map[string]interface{})
+	for _, v := range d.Get("environment").(*schema.Set).List() {
+		env := v.(map[string]interface{})
+		envConf[env["name"].(string)] = map[string]interface{}{
+			"SpecName":     env["spec_name"],
+			"AllowedHosts": expandStringList(env["allowed_hosts"].([]interface{})),
+		}
+	}
+
+	b, err := json.Marshal(envConf)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,180 @@
+package aliyun
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"os"
+)
+
+func resourceAliyunDcdnErCode() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   resourceAliyunDcdnErCodeRead,
+		CreateContext: resourceAliyunDcdnErCodeCreate,
+		UpdateContext: resourceAliyunDcdnErCodeUpdate,
+		DeleteContext: resourceAliyunDcdnErCodeDelete,
+		CustomizeDiff: resourceAliyunDcdnErCodeCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"routine": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"source_file": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"source_sha256": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"environments": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice([]string{"staging", "production"}, false),
+				},
+			},
+			"code_revision": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// The code is tracked by the hash of source_file rather than its path, so a
+// rebuild producing identical code plans no change.
+func resourceAliyunDcdnErCodeCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("source_file") {
+		return d.SetNewComputed("source_sha256")
+	}
+
+	code, err := os.ReadFile(d.Get("source_file").(string))
+	if err != nil {
+		// The file may be produced by a build step running after the plan.
+		return d.SetNewComputed("source_sha256")
+	}
+
+	hash := sha256.Sum256(code)
+	if hex.EncodeToString(hash[:]) == d.Get("source_sha256").(string) && !d.HasChange("description") {
+		return nil
+	}
+
+	if err := d.SetNew("source_sha256", hex.EncodeToString(hash[:])); err != nil {
+		return err
+	}
+	return d.SetNewComputed("code_revision")
+}
+
+// Published revisions keep serving traffic until another revision replaces
+// them, so the code is only dropped from state.
+func resourceAliyunDcdnErCodeDelete(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	d.SetId("")
+
+	return diags
+}
+
+func resourceAliyunDcdnErCodeUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn := m.(Client).dcdnconn
+
+	revision := d.Get("code_revision").(string)
+	if d.HasChanges("source_sha256", "description") {
+		var err error
+		revision, err = uploadDcdnErCode(ctx, d, m)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if err := publishDcdnRoutineCode(conn, d.Id(), revision, expandStringList(d.Get("environments").(*schema.Set).List())); err != nil {
+			return diag.FromErr(err)
+		}
+	} else if d.HasChange("environments") {
+		o, n := d.GetChange("environments")
+		envs := n.(*schema.Set).Difference(o.(*schema.Set)).List()
+		if err := publishDcdnRoutineCode(conn, d.Id(), revision, expandStringList(envs)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceAliyunDcdnErCodeRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnErCodeCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn := m.(Client).dcdnconn
+
+	d.SetId(d.Get("routine").(string))
+
+	revision, err := uploadDcdnErCode(ctx, d, m)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := publishDcdnRoutineCode(conn, d.Id(), revision, expandStringList(d.Get("environments").(*schema.Set).List())); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceAliyunDcdnErCodeRead(ctx, d, m)
+}
+
+// Read reports in environments only the environments still serving
+// code_revision, so a revision published outside Terraform shows as drift.
+func resourceAliyunDcdnErCodeRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	routine, err := describeDcdnRoutine(conn, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if routine == nil {
+		log.Printf("[WARN] DCDN routine %s not found, removing from state", d.Id())
+		d.SetId("")
+		return diags
+	}
+
+	environments := make([]string, 0)
+	for _, env := range []string{"staging", "production"} {
+		if dcdnRoutineEnvCodeRevision(routine, env) == d.Get("code_revision").(string) {
+			environments = append(environments, env)
+		}
+	}
+
+	d.Set("routine", d.Id())
+	if err := d.Set("environments", environments); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+func uploadDcdnErCode(ctx context.Context, d *schema.ResourceData, m interface{}) (string, error) {
+	conn := m.(Client).dcdnconn
+
+	code, err := os.ReadFile(d.Get("source_file").(string))
+	if err != nil {
+		return "", err
+	}
+
+	revision, err := uploadDcdnRoutineCode(ctx, conn, d.Id(), d.Get("description").(string), code)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(code)
+	d.Set("source_sha256", hex.EncodeToString(hash[:]))
+	d.Set("code_revision", revision)
+
+	return revision, nil
+}
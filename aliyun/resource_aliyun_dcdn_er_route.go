@@ -0,0 +1,174 @@
+package aliyun
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const dcdnErRouteFunctionName = "edge_function"
+
+func resourceAliyunDcdnErRoute() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   resourceAliyunDcdnErRouteRead,
+		CreateContext: resourceAliyunDcdnErRouteCreate,
+		UpdateContext: resourceAliyunDcdnErRouteUpdate,
+		DeleteContext: resourceAliyunDcdnErRouteDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"domain_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"routine": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"path": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "/*",
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^/`), "must start with /"),
+			},
+			"priority": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntBetween(0, 1),
+			},
+			"position": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "head",
+				ValidateFunc: validation.StringInSlice([]string{"head", "foot"}, false),
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+		},
+	}
+}
+
+func resourceAliyunDcdnErRouteDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	parts, err := ParseResourceId(d.Id(), 2)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = deleteDcdnDomainConfigs(conn, parts[0], []string{parts[1]})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func resourceAliyunDcdnErRouteUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChanges("routine", "path", "priority", "position", "enabled") {
+		parts, err := ParseResourceId(d.Id(), 2)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if err := setDcdnErRoute(ctx, d, m, parts[1], d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceAliyunDcdnErRouteRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnErRouteCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if err := setDcdnErRoute(ctx, d, m, "", d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceAliyunDcdnErRouteRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnErRouteRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	parts, err := ParseResourceId(d.Id(), 2)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	configs, err := describeDcdnDomainConfigs(conn, parts[0], []string{dcdnErRouteFunctionName})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, config := range configs {
+		if config.ConfigId != parts[1] {
+			continue
+		}
+		args := config.Args
+		priority, _ := strconv.Atoi(args["pri"])
+
+		d.Set("domain_name", parts[0])
+		d.Set("routine", args["name"])
+		d.Set("path", args["rule"])
+		d.Set("priority", priority)
+		d.Set("position", args["pos"])
+		d.Set("enabled", args["enable"] == "on")
+
+		return diags
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// setDcdnErRoute binds the routine through an edge_function config, updating
+// configId in place when set, and stores the config id in the resource id
+// before waiting for it to be deployed.
+func setDcdnErRoute(ctx context.Context, d *schema.ResourceData, m interface{}, configId string, timeout time.Duration) error {
+	conn := m.(Client).dcdnconn
+	domain := d.Get("domain_name").(string)
+
+	function, err := domainFunction(dcdnErRouteFunctionName, configId, map[string]string{
+		"enable": OnOff(d.Get("enabled").(bool)),
+		"name":   d.Get("routine").(string),
+		"rule":   d.Get("path").(string),
+		"pri":    strconv.Itoa(d.Get("priority").(int)),
+		"pos":    d.Get("position").(string),
+	})
+	if err != nil {
+		return err
+	}
+
+	functions := []map[string]interface{}{function}
+
+	ids, err := setDcdnDomainFunctions(ctx, conn, domain, functions, timeout)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s%s%s", domain, COLON_SEPARATED, ids[0]))
+
+	return waitForDcdnDomainConfigs(ctx, conn, domain, functions, ids, timeout)
+}
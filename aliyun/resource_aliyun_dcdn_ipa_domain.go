@@ -0,0 +1,254 @@
+package aliyun
+
+import (
+	"context"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"time"
+)
+
+func resourceAliyunDcdnIpaDomain() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   resourceAliyunDcdnIpaDomainRead,
+		CreateContext: resourceAliyunDcdnIpaDomainCreate,
+		UpdateContext: resourceAliyunDcdnIpaDomainUpdate,
+		DeleteContext: resourceAliyunDcdnIpaDomainDelete,
+		CustomizeDiff: customizeDomainSourcesDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"resource_group_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"domain_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"protocol": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"tcp", "udp"}, false),
+			},
+			"scope": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"domestic", "global", "overseas"}, false),
+				Default:      "domestic",
+			},
+			"cname": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"sources": domainSourcesSchema(),
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+	}
+}
+
+func resourceAliyunDcdnIpaDomainDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	request := dcdn.CreateDeleteDcdnIpaDomainRequest()
+	request.DomainName = d.Id()
+
+	_, err := conn.DeleteDcdnIpaDomain(request)
+	if err != nil {
+		if IsExpectedErrors(err, []string{"InvalidDomain.NotFound"}) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	err = waitForDcdnIpaDomainStatus(ctx, conn, d.Id(), domainDeletePendingStatus, []string{}, d.Timeout(schema.TimeoutDelete))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func resourceAliyunDcdnIpaDomainUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn := m.(Client).dcdnconn
+
+	o, _ := d.GetChange("enabled")
+	enabled := o.(bool)
+	if d.HasChange("enabled") && d.Get("enabled").(bool) {
+		if err := setDcdnIpaDomainEnabled(ctx, conn, d.Id(), true, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.FromErr(err)
+		}
+		enabled = true
+	}
+
+	updateDomain := false
+	request := dcdn.CreateUpdateDcdnIpaDomainRequest()
+	if d.HasChange("resource_group_id") {
+		updateDomain = true
+		request.ResourceGroupId = d.Get("resource_group_id").(string)
+	}
+	if d.HasChange("sources") {
+		updateDomain = true
+		sources, err := convertSourcesToString(d.Get("sources").(*schema.Set).List())
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		request.Sources = sources
+	}
+
+	if updateDomain {
+		request.DomainName = d.Id()
+		_, err := conn.UpdateDcdnIpaDomain(request)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		err = waitForDcdnIpaDomainStatus(ctx, conn, d.Id(), domainPendingStatus, []string{domainTargetStatus(enabled)}, d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("enabled") && !d.Get("enabled").(bool) {
+		if err := setDcdnIpaDomainEnabled(ctx, conn, d.Id(), false, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceAliyunDcdnIpaDomainRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnIpaDomainCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn := m.(Client).dcdnconn
+
+	domain := d.Get("domain_name").(string)
+
+	request := dcdn.CreateAddDcdnIpaDomainRequest()
+	request.DomainName = domain
+	request.Protocol = d.Get("protocol").(string)
+
+	if v, ok := d.GetOk("resource_group_id"); ok {
+		request.ResourceGroupId = v.(string)
+	}
+
+	if v, ok := d.GetOk("scope"); ok {
+		request.Scope = v.(string)
+	}
+
+	sources, err := convertSourcesToString(d.Get("sources").(*schema.Set).List())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	request.Sources = sources
+
+	_, err = conn.AddDcdnIpaDomain(request)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(domain)
+
+	err = waitForDcdnIpaDomainStatus(ctx, conn, domain, domainPendingStatus, []string{"online"}, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if !d.Get("enabled").(bool) {
+		if err := setDcdnIpaDomainEnabled(ctx, conn, domain, false, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceAliyunDcdnIpaDomainRead(ctx, d, m)
+}
+
+// DescribeDcdnIpaDomainDetail does not report the protocol, which is kept
+// as configured.
+func resourceAliyunDcdnIpaDomainRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	request := dcdn.CreateDescribeDcdnIpaDomainDetailRequest()
+	request.DomainName = d.Id()
+
+	res, err := conn.DescribeDcdnIpaDomainDetail(request)
+	if err != nil {
+		if IsExpectedErrors(err, []string{"InvalidDomain.NotFound"}) {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	d.Set("domain_name", d.Id())
+	d.Set("resource_group_id", res.DomainDetail.ResourceGroupId)
+	d.Set("scope", res.DomainDetail.Scope)
+	if err := d.Set("sources", flattenDcdnSources(res.DomainDetail.Sources.Source)); err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("cname", res.DomainDetail.Cname)
+	d.Set("enabled", res.DomainDetail.DomainStatus != "offline")
+
+	return diags
+}
+
+func setDcdnIpaDomainEnabled(ctx context.Context, conn *dcdn.Client, domain string, enabled bool, timeout time.Duration) error {
+	if enabled {
+		request := dcdn.CreateStartDcdnIpaDomainRequest()
+		request.DomainName = domain
+		if _, err := conn.StartDcdnIpaDomain(request); err != nil {
+			return err
+		}
+		return waitForDcdnIpaDomainStatus(ctx, conn, domain, domainStartPendingStatus, []string{"online"}, timeout)
+	}
+
+	request := dcdn.CreateStopDcdnIpaDomainRequest()
+	request.DomainName = domain
+	if _, err := conn.StopDcdnIpaDomain(request); err != nil {
+		return err
+	}
+	return waitForDcdnIpaDomainStatus(ctx, conn, domain, domainStopPendingStatus, []string{"offline"}, timeout)
+}
+
+func dcdnIpaDomainStateRefreshFunc(conn *dcdn.Client, domain string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		request := dcdn.CreateDescribeDcdnIpaDomainDetailRequest()
+		request.DomainName = domain
+
+		res, err := conn.DescribeDcdnIpaDomainDetail(request)
+		if err != nil {
+			if IsExpectedErrors(err, []string{"InvalidDomain.NotFound"}) {
+				return nil, "", nil
+			}
+			return nil, "", err
+		}
+
+		return res, res.DomainDetail.DomainStatus, nil
+	}
+}
+
+func waitForDcdnIpaDomainStatus(ctx context.Context, conn *dcdn.Client, domain string, pending, target []string, timeout time.Duration) error {
+	return waitForDomainStatus(ctx, "dcdn ipa", domain, dcdnIpaDomainStateRefreshFunc(conn, domain), pending, target, timeout)
+}
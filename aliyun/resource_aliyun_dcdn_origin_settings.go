@@ -0,0 +1,255 @@
+package aliyun
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"strconv"
+	"time"
+)
+
+var dcdnOriginFunctionNames = []string{"set_req_host_header", "forward_scheme", "https_origin_sni", "origin_request_header", "origin_response_header", "forward_timeout", "range"}
+
+func resourceAliyunDcdnOriginSettings() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   resourceAliyunDcdnOriginSettingsRead,
+		CreateContext: resourceAliyunDcdnOriginSettingsCreate,
+		UpdateContext: resourceAliyunDcdnOriginSettingsUpdate,
+		DeleteContext: resourceAliyunDcdnOriginSettingsDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importDcdnDomainConfigs(dcdnOriginFunctionNames),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"domain_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"host_header": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"protocol": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"follow", "http", "https"}, false),
+			},
+			"sni": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"request_headers": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     dcdnOriginHeaderSchema(),
+			},
+			"response_headers": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     dcdnOriginHeaderSchema(),
+			},
+			"timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 900),
+			},
+			"range": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"on", "off", "force"}, false),
+			},
+			"config_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+		},
+	}
+}
+
+func dcdnOriginHeaderSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"value": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+	}
+}
+
+func resourceAliyunDcdnOriginSettingsDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	err := deleteDcdnDomainConfigs(conn, d.Id(), expandStringList(d.Get("config_ids").([]interface{})))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func resourceAliyunDcdnOriginSettingsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChanges("host_header", "protocol", "sni", "request_headers", "response_headers", "timeout", "range") {
+		if err := setDcdnOriginSettings(ctx, d, m, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceAliyunDcdnOriginSettingsRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnOriginSettingsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d.SetId(d.Get("domain_name").(string))
+
+	if err := setDcdnOriginSettings(ctx, d, m, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceAliyunDcdnOriginSettingsRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnOriginSettingsRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	configs, err := describeTrackedDcdnDomainConfigs(conn, d, dcdnOriginFunctionNames)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	hostHeader, protocol, sni, rangeOption, timeout := "", "", "", "", 0
+	requestHeaders := make([]map[string]interface{}, 0)
+	responseHeaders := make([]map[string]interface{}, 0)
+	configIds := make([]string, 0, len(configs))
+
+	for _, config := range configs {
+		configIds = append(configIds, config.ConfigId)
+		args := config.Args
+		switch config.FunctionName {
+		case "set_req_host_header":
+			hostHeader = args["domain_name"]
+		case "forward_scheme":
+			if args["enable"] == "on" {
+				protocol = args["scheme_origin"]
+			}
+		case "https_origin_sni":
+			if args["enabled"] == "on" {
+				sni = args["https_origin_sni"]
+			}
+		case "origin_request_header":
+			requestHeaders = append(requestHeaders, map[string]interface{}{
+				"name":  args["header_name"],
+				"value": args["header_value"],
+			})
+		case "origin_response_header":
+			responseHeaders = append(responseHeaders, map[string]interface{}{
+				"name":  args["header_name"],
+				"value": args["header_value"],
+			})
+		case "forward_timeout":
+			timeout, _ = strconv.Atoi(args["forward_timeout"])
+		case "range":
+			rangeOption = args["enable"]
+		}
+	}
+
+	d.Set("domain_name", d.Id())
+	d.Set("host_header", hostHeader)
+	d.Set("protocol", protocol)
+	d.Set("sni", sni)
+	d.Set("timeout", timeout)
+	d.Set("range", rangeOption)
+	if err := d.Set("request_headers", requestHeaders); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("response_headers", responseHeaders); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("config_ids", configIds); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+func setDcdnOriginSettings(ctx context.Context, d *schema.ResourceData, m interface{}, timeout time.Duration) error {
+	conn := m.(Client).dcdnconn
+	domain := d.Id()
+
+	configs, err := describeDcdnDomainConfigs(conn, domain, dcdnOriginFunctionNames)
+	if err != nil {
+		return err
+	}
+	tracked := expandStringList(d.Get("config_ids").([]interface{}))
+	configIds := make(map[string][]string, len(dcdnOriginFunctionNames))
+	for _, config := range filterDomainConfigs(configs, tracked) {
+		configIds[config.FunctionName] = append(configIds[config.FunctionName], config.ConfigId)
+	}
+
+	args := make(map[string][]map[string]string, len(dcdnOriginFunctionNames))
+	if v, ok := d.GetOk("host_header"); ok {
+		args["set_req_host_header"] = []map[string]string{{"domain_name": v.(string)}}
+	}
+	if v, ok := d.GetOk("protocol"); ok {
+		args["forward_scheme"] = []map[string]string{{"enable": "on", "scheme_origin": v.(string)}}
+	}
+	if v, ok := d.GetOk("sni"); ok {
+		args["https_origin_sni"] = []map[string]string{{"enabled": "on", "https_origin_sni": v.(string)}}
+	}
+	if v, ok := d.GetOk("timeout"); ok {
+		args["forward_timeout"] = []map[string]string{{"forward_timeout": strconv.Itoa(v.(int))}}
+	}
+	if v, ok := d.GetOk("range"); ok {
+		args["range"] = []map[string]string{{"enable": v.(string)}}
+	}
+	for attribute, functionName := range map[string]string{"request_headers": "origin_request_header", "response_headers": "origin_response_header"} {
+		for _, v := range d.Get(attribute).(*schema.Set).List() {
+			header := v.(map[string]interface{})
+			args[functionName] = append(args[functionName], map[string]string{
+				"header_operation_type": "add",
+				"header_name":           header["name"].(string),
+				"header_value":          header["value"].(string),
+				"duplicate":             "off",
+			})
+		}
+	}
+
+	// The headers may be configured any number of times, every other
+	// function only once per domain.
+	var added []string
+	var functions []map[string]interface{}
+	var obsolete []string
+	for _, functionName := range dcdnOriginFunctionNames {
+		if functionName != "origin_request_header" && functionName != "origin_response_header" &&
+			len(configIds[functionName]) == 0 && len(args[functionName]) > 0 {
+			added = append(added, functionName)
+		}
+		f, o, err := reuseDcdnDomainConfigs(functionName, configIds[functionName], args[functionName])
+		if err != nil {
+			return err
+		}
+		functions = append(functions, f...)
+		obsolete = append(obsolete, o...)
+	}
+
+	if err := checkUntrackedDcdnDomainConfigs(domain, configs, tracked, added); err != nil {
+		return err
+	}
+
+	return setTrackedDcdnDomainConfigs(ctx, conn, d, functions, nil, obsolete, timeout)
+}
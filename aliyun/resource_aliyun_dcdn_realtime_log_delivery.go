@@ -0,0 +1,281 @@
+package aliyun
+
+import (
+	"context"
+	"fmt"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/responses"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SLS regions DCDN can deliver real-time logs to.
+var dcdnRealtimeLogSlsRegions = []string{
+	"cn-hangzhou", "cn-shanghai", "cn-qingdao", "cn-beijing", "cn-zhangjiakou", "cn-huhehaote", "cn-shenzhen", "cn-chengdu", "cn-hongkong",
+	"ap-southeast-1", "ap-southeast-3", "ap-southeast-5", "ap-northeast-1", "ap-south-1", "eu-central-1", "eu-west-1", "us-west-1", "us-east-1", "me-east-1",
+}
+
+var dcdnRealtimeLogNotFoundErrors = []string{"InvalidProject.NotFound", "ProjectNotFound", "NotFound"}
+
+func resourceAliyunDcdnRealtimeLogDelivery() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   resourceAliyunDcdnRealtimeLogDeliveryRead,
+		CreateContext: resourceAliyunDcdnRealtimeLogDeliveryCreate,
+		UpdateContext: resourceAliyunDcdnRealtimeLogDeliveryUpdate,
+		DeleteContext: resourceAliyunDcdnRealtimeLogDeliveryDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceAliyunDcdnRealtimeLogDeliveryImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"project_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"business_type": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  "cdn_log_access_l1",
+			},
+			"domain_names": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"sls_project": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"sls_logstore": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"sls_region": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(dcdnRealtimeLogSlsRegions, false),
+			},
+			"data_center": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "cn",
+				ValidateFunc: validation.StringInSlice([]string{"cn", "sg"}, false),
+			},
+			"sampling_rate": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Default:      1.0,
+				ValidateFunc: validation.FloatBetween(0.001, 1),
+			},
+			"suspended": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// Deliveries can be imported by project name or by one of their domains.
+func resourceAliyunDcdnRealtimeLogDeliveryImport(_ context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	conn := m.(Client).dcdnconn
+
+	if !strings.Contains(d.Id(), ".") {
+		return []*schema.ResourceData{d}, nil
+	}
+
+	request := dcdn.CreateListDcdnRealTimeDeliveryProjectRequest()
+	request.DomainName = d.Id()
+
+	res, err := conn.ListDcdnRealTimeDeliveryProject(request)
+	if err != nil {
+		return nil, err
+	}
+	for _, project := range res.Content.Projects {
+		if project.ProjectName != "" {
+			d.SetId(project.ProjectName)
+			return []*schema.ResourceData{d}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no real-time log project found for domain %s", d.Id())
+}
+
+func resourceAliyunDcdnRealtimeLogDeliveryDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	request := dcdn.CreateDeleteDcdnRealTimeLogProjectRequest()
+	request.ProjectName = d.Id()
+
+	_, err := conn.DeleteDcdnRealTimeLogProject(request)
+	if err != nil && !IsExpectedErrors(err, dcdnRealtimeLogNotFoundErrors) {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func resourceAliyunDcdnRealtimeLogDeliveryUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn := m.(Client).dcdnconn
+
+	if d.HasChanges("sls_project", "sls_logstore", "sls_region") {
+		if err := checkSlsLogstore(ctx, m.(Client).slsconn, d.Get("sls_region").(string), d.Get("sls_project").(string), d.Get("sls_logstore").(string)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChanges("domain_names", "sls_project", "sls_logstore", "sls_region", "data_center", "sampling_rate") {
+		request := dcdn.CreateUpdateDcdnSLSRealtimeLogDeliveryRequest()
+		request.ProjectName = d.Id()
+		request.DomainName = dcdnRealtimeLogDomainNames(d)
+		request.SLSProject = d.Get("sls_project").(string)
+		request.SLSLogStore = d.Get("sls_logstore").(string)
+		request.SLSRegion = d.Get("sls_region").(string)
+		request.DataCenter = d.Get("data_center").(string)
+		request.SamplingRate = strconv.FormatFloat(d.Get("sampling_rate").(float64), 'f', -1, 64)
+
+		if _, err := conn.UpdateDcdnSLSRealtimeLogDelivery(request); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChanges("domain_names", "suspended") {
+		if err := setDcdnRealtimeLogDeliveryEnabled(conn, strings.Split(dcdnRealtimeLogDomainNames(d), ","), !d.Get("suspended").(bool)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceAliyunDcdnRealtimeLogDeliveryRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnRealtimeLogDeliveryCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn := m.(Client).dcdnconn
+
+	if err := checkSlsLogstore(ctx, m.(Client).slsconn, d.Get("sls_region").(string), d.Get("sls_project").(string), d.Get("sls_logstore").(string)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	request := dcdn.CreateCreateDcdnSLSRealTimeLogDeliveryRequest()
+	request.ProjectName = d.Get("project_name").(string)
+	request.BusinessType = d.Get("business_type").(string)
+	request.DomainName = dcdnRealtimeLogDomainNames(d)
+	request.SLSProject = d.Get("sls_project").(string)
+	request.SLSLogStore = d.Get("sls_logstore").(string)
+	request.SLSRegion = d.Get("sls_region").(string)
+	request.DataCenter = d.Get("data_center").(string)
+	request.SamplingRate = strconv.FormatFloat(d.Get("sampling_rate").(float64), 'f', -1, 64)
+
+	res, err := conn.CreateDcdnSLSRealTimeLogDelivery(request)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(request.ProjectName)
+
+	var failed []string
+	for _, domain := range res.Content.Domains {
+		if domain.Status != "" && domain.Status != "success" {
+			failed = append(failed, fmt.Sprintf("%s: %s", domain.DomainName, domain.Desc))
+		}
+	}
+	if len(failed) > 0 {
+		return diag.Errorf("error binding domains to dcdn real-time log delivery %s: %s", d.Id(), strings.Join(failed, "; "))
+	}
+
+	if d.Get("suspended").(bool) {
+		if err := setDcdnRealtimeLogDeliveryEnabled(conn, strings.Split(request.DomainName, ","), false); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceAliyunDcdnRealtimeLogDeliveryRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnRealtimeLogDeliveryRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	request := dcdn.CreateDescribeDcdnSLSRealtimeLogDeliveryRequest()
+	request.ProjectName = d.Id()
+
+	res, err := conn.DescribeDcdnSLSRealtimeLogDelivery(request)
+	if err != nil {
+		if IsExpectedErrors(err, dcdnRealtimeLogNotFoundErrors) {
+			log.Printf("[WARN] DCDN real-time log delivery %s not found, removing from state", d.Id())
+			d.SetId("")
+			return diags
+		}
+		return diag.FromErr(err)
+	}
+	if res.Content.ProjectName == "" {
+		log.Printf("[WARN] DCDN real-time log delivery %s not found, removing from state", d.Id())
+		d.SetId("")
+		return diags
+	}
+
+	var domainNames []string
+	if res.Content.DomainName != "" {
+		domainNames = strings.Split(res.Content.DomainName, ",")
+	}
+
+	d.Set("project_name", res.Content.ProjectName)
+	d.Set("business_type", res.Content.BusinessType)
+	d.Set("sls_project", res.Content.SLSProject)
+	d.Set("sls_logstore", res.Content.SLSLogStore)
+	d.Set("sls_region", res.Content.SLSRegion)
+	d.Set("data_center", res.Content.DataCenter)
+	if rate, err := strconv.ParseFloat(res.Content.SamplingRate, 64); err == nil {
+		d.Set("sampling_rate", rate)
+	}
+	d.Set("status", res.Content.Status)
+	d.Set("suspended", res.Content.Status == "offline")
+	if err := d.Set("domain_names", domainNames); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+func dcdnRealtimeLogDomainNames(d *schema.ResourceData) string {
+	domainNames := expandStringList(d.Get("domain_names").(*schema.Set).List())
+	sort.Strings(domainNames)
+	return strings.Join(domainNames, ",")
+}
+
+// The dcdn package of the SDK version in use lacks the APIs suspending and
+// resuming a delivery, so their request is declared here the way the SDK
+// generates it.
+type dcdnRealtimeLogDeliveryDomainRequest struct {
+	*requests.RpcRequest
+	Domain string `position:"Query" name:"Domain"`
+}
+
+func setDcdnRealtimeLogDeliveryEnabled(conn *dcdn.Client, domains []string, enabled bool) error {
+	action := "DisableDcdnRealtimeLogDelivery"
+	if enabled {
+		action = "EnableDcdnRealtimeLogDelivery"
+	}
+
+	request := &dcdnRealtimeLogDeliveryDomainRequest{RpcRequest: &requests.RpcRequest{}}
+	request.InitWithApiInfo("dcdn", "2018-01-15", action, "", "")
+	request.Method = requests.POST
+	request.Domain = strings.Join(domains, ",")
+
+	return conn.DoAction(request, &responses.BaseResponse{})
+}
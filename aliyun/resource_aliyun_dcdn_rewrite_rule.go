@@ -0,0 +1,276 @@
+package aliyun
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"time"
+)
+
+const dcdnRewriteFunctionName = "host_redirect"
+
+func resourceAliyunDcdnRewriteRule() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   resourceAliyunDcdnRewriteRuleRead,
+		CreateContext: resourceAliyunDcdnRewriteRuleCreate,
+		UpdateContext: resourceAliyunDcdnRewriteRuleUpdate,
+		DeleteContext: resourceAliyunDcdnRewriteRuleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: importDcdnDomainConfigs([]string{dcdnRewriteFunctionName}),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"domain_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"rule": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"source_regex": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validateDcdnRewriteRegex,
+						},
+						"target": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"flag": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "redirect",
+							ValidateFunc: validation.StringInSlice([]string{"redirect", "break", "enhance_break"}, false),
+						},
+					},
+				},
+			},
+			"config_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+		},
+	}
+}
+
+func resourceAliyunDcdnRewriteRuleDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	err := deleteDcdnDomainConfigs(conn, d.Id(), expandStringList(d.Get("config_ids").([]interface{})))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func resourceAliyunDcdnRewriteRuleUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChange("rule") {
+		if err := setDcdnRewriteRules(ctx, d, m, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceAliyunDcdnRewriteRuleRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnRewriteRuleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	d.SetId(d.Get("domain_name").(string))
+
+	if err := setDcdnRewriteRules(ctx, d, m, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceAliyunDcdnRewriteRuleRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnRewriteRuleRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	configs, err := describeTrackedDcdnDomainConfigs(conn, d, []string{dcdnRewriteFunctionName})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if len(configs) == 0 {
+		d.SetId("")
+		return diags
+	}
+
+	rules := make([]map[string]interface{}, 0, len(configs))
+	configIds := make([]string, 0, len(configs))
+	for _, config := range configs {
+		configIds = append(configIds, config.ConfigId)
+		args := config.Args
+		rules = append(rules, map[string]interface{}{
+			"source_regex": args["regex"],
+			"target":       args["replacement"],
+			"flag":         args["flag"],
+		})
+	}
+
+	d.Set("domain_name", d.Id())
+	if err := d.Set("rule", rules); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("config_ids", configIds); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+// setDcdnRewriteRules rewrites the existing rules in place, oldest first, and
+// only appends or deletes rules at the tail. DCDN evaluates rewrite rules in
+// the order they were created, so this keeps the configured order even when a
+// rule is inserted in the middle of the list.
+func setDcdnRewriteRules(ctx context.Context, d *schema.ResourceData, m interface{}, timeout time.Duration) error {
+	conn := m.(Client).dcdnconn
+
+	configs, err := describeTrackedDcdnDomainConfigs(conn, d, []string{dcdnRewriteFunctionName})
+	if err != nil {
+		return err
+	}
+	configIds := make([]string, 0, len(configs))
+	for _, config := range configs {
+		configIds = append(configIds, config.ConfigId)
+	}
+
+	var args []map[string]string
+	for _, v := range d.Get("rule").([]interface{}) {
+		rule := v.(map[string]interface{})
+		args = append(args, map[string]string{
+			"regex":       rule["source_regex"].(string),
+			"replacement": rule["target"].(string),
+			"flag":        rule["flag"].(string),
+		})
+	}
+
+	functions, obsolete, err := reuseDcdnDomainConfigs(dcdnRewriteFunctionName, configIds, args)
+	if err != nil {
+		return err
+	}
+
+	return setTrackedDcdnDomainConfigs(ctx, conn, d, functions, nil, obsolete, timeout)
+}
+
+func validateDcdnRewriteRegex(v interface{}, path cty.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+	value := v.(string)
+
+	if _, err := regexp.Compile(value); err != nil {
+		return append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Invalid rewrite regular expression",
+			Detail:        fmt.Sprintf("%q does not compile: %s", value, err),
+			AttributePath: path,
+		})
+	}
+
+	re, _ := syntax.Parse(value, syntax.Perl)
+	if r, ok := dcdnRegexAnchoredFirstRune(re); ok && r != '/' {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Warning,
+			Summary:       "Rewrite regular expression can never match",
+			Detail:        fmt.Sprintf("DCDN matches %q against the request URI, which always starts with \"/\".", value),
+			AttributePath: path,
+		})
+	}
+
+	for _, construct := range unsupportedDcdnRegexConstructs(re) {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Warning,
+			Summary:       "Rewrite regular expression uses a construct DCDN does not support",
+			Detail:        fmt.Sprintf("%q uses %s.", value, construct),
+			AttributePath: path,
+		})
+	}
+
+	return diags
+}
+
+// unsupportedDcdnRegexConstructs reports the parts of re that compile in Go
+// but have no effect in a DCDN rewrite rule: the rule is matched against the
+// URI without its query string, and the target can only refer to the first
+// nine groups by number.
+func unsupportedDcdnRegexConstructs(re *syntax.Regexp) []string {
+	var constructs []string
+	seen := map[string]bool{}
+	add := func(construct string) {
+		if !seen[construct] {
+			seen[construct] = true
+			constructs = append(constructs, construct)
+		}
+	}
+
+	var walk func(*syntax.Regexp)
+	walk = func(re *syntax.Regexp) {
+		switch re.Op {
+		case syntax.OpCapture:
+			if re.Name != "" {
+				add("named capture groups, which the target cannot reference")
+			}
+		case syntax.OpLiteral:
+			if strings.ContainsRune(string(re.Rune), '?') {
+				add("a literal \"?\", but the query string is not part of the matched URI")
+			}
+		}
+		for _, sub := range re.Sub {
+			walk(sub)
+		}
+	}
+	walk(re)
+
+	if re.MaxCap() > 9 {
+		add("more than nine capture groups, but the target can only reference $1 to $9")
+	}
+
+	return constructs
+}
+
+// dcdnRegexAnchoredFirstRune returns the character a match of re must start
+// with when re is anchored with ^ and that character is fixed.
+func dcdnRegexAnchoredFirstRune(re *syntax.Regexp) (rune, bool) {
+	if re.Op != syntax.OpConcat || len(re.Sub) < 2 || re.Sub[0].Op != syntax.OpBeginText && re.Sub[0].Op != syntax.OpBeginLine {
+		return 0, false
+	}
+	return dcdnRegexFirstRune(re.Sub[1])
+}
+
+func dcdnRegexFirstRune(re *syntax.Regexp) (rune, bool) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return re.Rune[0], true
+	case syntax.OpCharClass:
+		if len(re.Rune) == 2 && re.Rune[0] == re.Rune[1] {
+			return re.Rune[0], true
+		}
+	case syntax.OpCapture, syntax.OpPlus, syntax.OpConcat:
+		return dcdnRegexFirstRune(re.Sub[0])
+	case syntax.OpRepeat:
+		if re.Min > 0 {
+			return dcdnRegexFirstRune(re.Sub[0])
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,47 @@
+package aliyun
+
+import (
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"reflect"
+	"testing"
+)
+
+func TestValidateDcdnRewriteRegex(t *testing.T) {
+	cases := []struct {
+		regex string
+		want  []string
+	}{
+		{`^/images/(.*)$`, nil},
+		{`images`, nil},
+		{`^.*\.jpg$`, nil},
+		{`^(/a)/b`, nil},
+		{`^images/(.*)$`, []string{"Rewrite regular expression can never match"}},
+		{`^[a]b`, []string{"Rewrite regular expression can never match"}},
+		{`^(?:images)+`, []string{"Rewrite regular expression can never match"}},
+		{`^/(?P<name>\w+)$`, []string{"Rewrite regular expression uses a construct DCDN does not support"}},
+		{`^/a\?b=1`, []string{"Rewrite regular expression uses a construct DCDN does not support"}},
+		{`^/(a)(b)(c)(d)(e)(f)(g)(h)(i)(j)`, []string{"Rewrite regular expression uses a construct DCDN does not support"}},
+		{`^x(?P<name>a)\?`, []string{
+			"Rewrite regular expression can never match",
+			"Rewrite regular expression uses a construct DCDN does not support",
+			"Rewrite regular expression uses a construct DCDN does not support",
+		}},
+		{`^/(`, []string{"Invalid rewrite regular expression"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.regex, func(t *testing.T) {
+			var got []string
+			for _, d := range validateDcdnRewriteRegex(c.regex, cty.GetAttrPath("source_regex")) {
+				got = append(got, d.Summary)
+				if d.Summary == "Invalid rewrite regular expression" && d.Severity != diag.Error {
+					t.Errorf("%q is reported as a warning", c.regex)
+				}
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("validateDcdnRewriteRegex(%q) = %v, want %v", c.regex, got, c.want)
+			}
+		})
+	}
+}
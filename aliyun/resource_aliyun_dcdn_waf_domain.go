@@ -0,0 +1,204 @@
+package aliyun
+
+import (
+	"context"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"log"
+	"time"
+)
+
+func resourceAliyunDcdnWafDomain() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   resourceAliyunDcdnWafDomainRead,
+		CreateContext: resourceAliyunDcdnWafDomainCreate,
+		UpdateContext: resourceAliyunDcdnWafDomainUpdate,
+		DeleteContext: resourceAliyunDcdnWafDomainDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceAliyunDcdnWafDomainImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"domain_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"client_ip_tag": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"policy_ids": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+	}
+}
+
+// The WAF domain is imported with the custom policies bound to it. The
+// default policies DCDN binds to every protected domain are left out, as
+// they are not managed through policy_ids.
+func resourceAliyunDcdnWafDomainImport(_ context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	conn := m.(Client).dcdnconn
+
+	policyIds, err := describeDcdnWafDomainPolicyIds(conn, d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	custom := make([]string, 0, len(policyIds))
+	for _, policyId := range policyIds {
+		policy, err := describeDcdnWafPolicy(conn, policyId)
+		if err != nil {
+			return nil, err
+		}
+		if policy != nil && policy.PolicyType != "default" {
+			custom = append(custom, policyId)
+		}
+	}
+	if err := d.Set("policy_ids", custom); err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceAliyunDcdnWafDomainDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	for _, policyId := range d.Get("policy_ids").(*schema.Set).List() {
+		err := modifyDcdnWafPolicyDomains(conn, policyId.(string), "", d.Id())
+		if err != nil && !IsExpectedErrors(err, dcdnWafNotFoundErrors) {
+			return diag.FromErr(err)
+		}
+	}
+	if err := waitForDcdnWafDomainPolicies(ctx, conn, d.Id(), nil, expandStringList(d.Get("policy_ids").(*schema.Set).List()), d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	request := dcdn.CreateBatchSetDcdnWafDomainConfigsRequest()
+	request.DomainNames = d.Id()
+	request.DefenseStatus = "off"
+
+	_, err := conn.BatchSetDcdnWafDomainConfigs(request)
+	if err != nil && !IsExpectedErrors(err, dcdnWafNotFoundErrors) {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func resourceAliyunDcdnWafDomainUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn := m.(Client).dcdnconn
+
+	if d.HasChange("client_ip_tag") {
+		if err := setDcdnWafDomainConfigs(conn, d); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("policy_ids") {
+		o, n := d.GetChange("policy_ids")
+		unbound := expandStringList(o.(*schema.Set).Difference(n.(*schema.Set)).List())
+		for _, policyId := range unbound {
+			err := modifyDcdnWafPolicyDomains(conn, policyId, "", d.Id())
+			if err != nil && !IsExpectedErrors(err, dcdnWafNotFoundErrors) {
+				return diag.FromErr(err)
+			}
+		}
+		for _, policyId := range n.(*schema.Set).Difference(o.(*schema.Set)).List() {
+			if err := modifyDcdnWafPolicyDomains(conn, policyId.(string), d.Id(), ""); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+		if err := waitForDcdnWafDomainPolicies(ctx, conn, d.Id(), expandStringList(n.(*schema.Set).List()), unbound, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceAliyunDcdnWafDomainRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnWafDomainCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn := m.(Client).dcdnconn
+
+	d.SetId(d.Get("domain_name").(string))
+
+	if err := setDcdnWafDomainConfigs(conn, d); err != nil {
+		return diag.FromErr(err)
+	}
+
+	policyIds := expandStringList(d.Get("policy_ids").(*schema.Set).List())
+	for _, policyId := range policyIds {
+		if err := modifyDcdnWafPolicyDomains(conn, policyId, d.Id(), ""); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err := waitForDcdnWafDomainPolicies(ctx, conn, d.Id(), policyIds, nil, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceAliyunDcdnWafDomainRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnWafDomainRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	policyIds, err := describeDcdnWafDomainPolicyIds(conn, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if policyIds == nil {
+		log.Printf("[WARN] DCDN WAF domain %s not found, removing from state", d.Id())
+		d.SetId("")
+		return diags
+	}
+
+	clientIpTag, err := describeDcdnWafDomainClientIpTag(conn, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Only the policies the resource bound are read back, leaving out the
+	// default policies and any bound by other means.
+	managed := d.Get("policy_ids").(*schema.Set)
+	bound := make([]string, 0, len(policyIds))
+	for _, policyId := range policyIds {
+		if managed.Contains(policyId) {
+			bound = append(bound, policyId)
+		}
+	}
+
+	d.Set("domain_name", d.Id())
+	d.Set("client_ip_tag", clientIpTag)
+	if err := d.Set("policy_ids", bound); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+// setDcdnWafDomainConfigs adds the domain to DCDN WAF, which must happen
+// before any policy can be bound to it.
+func setDcdnWafDomainConfigs(conn *dcdn.Client, d *schema.ResourceData) error {
+	request := dcdn.CreateBatchSetDcdnWafDomainConfigsRequest()
+	request.DomainNames = d.Id()
+	request.DefenseStatus = "on"
+	request.ClientIpTag = d.Get("client_ip_tag").(string)
+
+	_, err := conn.BatchSetDcdnWafDomainConfigs(request)
+	return err
+}
@@ -0,0 +1,134 @@
+package aliyun
+
+import (
+	"context"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"strconv"
+)
+
+func resourceAliyunDcdnWafPolicy() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   resourceAliyunDcdnWafPolicyRead,
+		CreateContext: resourceAliyunDcdnWafPolicyCreate,
+		UpdateContext: resourceAliyunDcdnWafPolicyUpdate,
+		DeleteContext: resourceAliyunDcdnWafPolicyDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"defense_scene": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(dcdnWafDefenseScenes, false),
+			},
+			"policy_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "custom",
+				ValidateFunc: validation.StringInSlice([]string{"default", "custom"}, false),
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"domain_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"rule_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceAliyunDcdnWafPolicyDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	request := dcdn.CreateDeleteDcdnWafPolicyRequest()
+	request.PolicyId = requests.Integer(d.Id())
+
+	_, err := conn.DeleteDcdnWafPolicy(request)
+	if err != nil && !IsExpectedErrors(err, dcdnWafNotFoundErrors) {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func resourceAliyunDcdnWafPolicyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn := m.(Client).dcdnconn
+
+	if d.HasChanges("name", "enabled") {
+		request := dcdn.CreateModifyDcdnWafPolicyRequest()
+		request.PolicyId = requests.Integer(d.Id())
+		request.PolicyName = d.Get("name").(string)
+		request.PolicyStatus = OnOff(d.Get("enabled").(bool))
+
+		if _, err := conn.ModifyDcdnWafPolicy(request); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceAliyunDcdnWafPolicyRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnWafPolicyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn := m.(Client).dcdnconn
+
+	request := dcdn.CreateCreateDcdnWafPolicyRequest()
+	request.PolicyName = d.Get("name").(string)
+	request.DefenseScene = d.Get("defense_scene").(string)
+	request.PolicyType = d.Get("policy_type").(string)
+	request.PolicyStatus = OnOff(d.Get("enabled").(bool))
+
+	res, err := conn.CreateDcdnWafPolicy(request)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(strconv.FormatInt(res.PolicyId, 10))
+
+	return resourceAliyunDcdnWafPolicyRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnWafPolicyRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	policy, err := describeDcdnWafPolicy(conn, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if policy == nil {
+		log.Printf("[WARN] DCDN WAF policy %s not found, removing from state", d.Id())
+		d.SetId("")
+		return diags
+	}
+
+	d.Set("name", policy.PolicyName)
+	d.Set("defense_scene", policy.DefenseScene)
+	d.Set("policy_type", policy.PolicyType)
+	d.Set("enabled", policy.PolicyStatus == "on")
+	d.Set("domain_count", policy.DomainCount)
+	d.Set("rule_count", policy.RuleCount)
+
+	return diags
+}
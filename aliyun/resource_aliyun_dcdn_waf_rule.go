@@ -0,0 +1,386 @@
+package aliyun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"log"
+	"strings"
+)
+
+func resourceAliyunDcdnWafRule() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   resourceAliyunDcdnWafRuleRead,
+		CreateContext: resourceAliyunDcdnWafRuleCreate,
+		UpdateContext: resourceAliyunDcdnWafRuleUpdate,
+		DeleteContext: resourceAliyunDcdnWafRuleDelete,
+		CustomizeDiff: resourceAliyunDcdnWafRuleCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"policy_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"action": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"deny", "monitor", "js", "block"}, false),
+			},
+			"conditions": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 5,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"sub_key": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"op_value": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"values": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"rate_limit": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"target": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"sub_key": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"interval": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntBetween(5, 1800),
+						},
+						"threshold": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntAtLeast(2),
+						},
+						"ttl": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntBetween(60, 86400),
+						},
+						"status_code": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"status_count": {
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+						"status_ratio": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntBetween(1, 100),
+						},
+					},
+				},
+			},
+			"waf_group_ids": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"remote_addr": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateIpOrCidr,
+				},
+			},
+			"cn_region_list": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"other_region_list": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"defense_scene": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceAliyunDcdnWafRuleCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if len(d.Get("rate_limit").([]interface{})) > 0 && len(d.Get("conditions").([]interface{})) == 0 {
+		return fmt.Errorf("rate_limit requires at least one condition")
+	}
+	if len(d.Get("waf_group_ids").([]interface{})) > 0 && (len(d.Get("conditions").([]interface{})) > 0 || len(d.Get("rate_limit").([]interface{})) > 0) {
+		return fmt.Errorf("waf_group_ids selects a managed rule set and cannot be combined with conditions or rate_limit")
+	}
+
+	return nil
+}
+
+func resourceAliyunDcdnWafRuleDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	request := dcdn.CreateBatchDeleteDcdnWafRulesRequest()
+	request.RuleIds = d.Id()
+
+	_, err := conn.BatchDeleteDcdnWafRules(request)
+	if err != nil && !IsExpectedErrors(err, dcdnWafNotFoundErrors) {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func resourceAliyunDcdnWafRuleUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn := m.(Client).dcdnconn
+
+	if d.HasChanges("name", "enabled", "action", "conditions", "rate_limit", "waf_group_ids", "remote_addr", "cn_region_list", "other_region_list") {
+		config, err := json.Marshal(expandDcdnWafRuleConfig(d))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		request := dcdn.CreateModifyDcdnWafRuleRequest()
+		request.RuleId = requests.Integer(d.Id())
+		request.RuleName = d.Get("name").(string)
+		request.RuleStatus = OnOff(d.Get("enabled").(bool))
+		request.RuleConfig = string(config)
+
+		if _, err := conn.ModifyDcdnWafRule(request); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceAliyunDcdnWafRuleRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnWafRuleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn := m.(Client).dcdnconn
+
+	config := expandDcdnWafRuleConfig(d)
+	config.Name = d.Get("name").(string)
+	config.Status = OnOff(d.Get("enabled").(bool))
+	configs, err := json.Marshal([]dcdnWafRuleConfig{config})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	request := dcdn.CreateBatchCreateDcdnWafRulesRequest()
+	request.PolicyId = requests.Integer(d.Get("policy_id").(string))
+	request.RuleConfigs = string(configs)
+
+	res, err := conn.BatchCreateDcdnWafRules(request)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if len(res.RuleIds.RuleId) == 0 {
+		return diag.Errorf("no rule id returned when creating dcdn waf rule %s", d.Get("name").(string))
+	}
+
+	d.SetId(res.RuleIds.RuleId[0])
+
+	return resourceAliyunDcdnWafRuleRead(ctx, d, m)
+}
+
+func resourceAliyunDcdnWafRuleRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).dcdnconn
+
+	request := dcdn.CreateDescribeDcdnWafRuleRequest()
+	request.RuleId = requests.Integer(d.Id())
+
+	res, err := conn.DescribeDcdnWafRule(request)
+	if err != nil {
+		if IsExpectedErrors(err, dcdnWafNotFoundErrors) {
+			log.Printf("[WARN] DCDN WAF rule %s not found, removing from state", d.Id())
+			d.SetId("")
+			return diags
+		}
+		return diag.FromErr(err)
+	}
+
+	config := dcdnWafRuleConfig{}
+	if res.Rule.RuleConfig != "" {
+		if err := json.Unmarshal([]byte(res.Rule.RuleConfig), &config); err != nil {
+			return diag.Errorf("error parsing config of dcdn waf rule %s: %s", d.Id(), err)
+		}
+	}
+
+	conditions := make([]map[string]interface{}, 0, len(config.Conditions))
+	for _, condition := range config.Conditions {
+		conditions = append(conditions, map[string]interface{}{
+			"key":      condition.Key,
+			"sub_key":  condition.SubKey,
+			"op_value": condition.OpValue,
+			"values":   condition.Values,
+		})
+	}
+
+	rateLimit := make([]map[string]interface{}, 0, 1)
+	if config.RateLimit != nil {
+		rateLimit = append(rateLimit, map[string]interface{}{
+			"target":    config.RateLimit.Target,
+			"sub_key":   config.RateLimit.SubKey,
+			"interval":  config.RateLimit.Interval,
+			"threshold": config.RateLimit.Threshold,
+			"ttl":       config.RateLimit.Ttl,
+		})
+		if status := config.RateLimit.Status; status != nil {
+			rateLimit[0]["status_code"] = status.Code
+			rateLimit[0]["status_count"] = status.Count
+			rateLimit[0]["status_ratio"] = status.Ratio
+		}
+	}
+
+	d.Set("policy_id", fmt.Sprint(res.Rule.PolicyId))
+	d.Set("name", res.Rule.RuleName)
+	d.Set("enabled", res.Rule.RuleStatus == "on")
+	d.Set("defense_scene", res.Rule.DefenseScene)
+	d.Set("action", config.Action)
+	if err := d.Set("conditions", conditions); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("rate_limit", rateLimit); err != nil {
+		return diag.FromErr(err)
+	}
+	for attribute, v := range map[string]string{
+		"waf_group_ids":     config.WafGroupIds,
+		"remote_addr":       config.RemoteAddr,
+		"cn_region_list":    config.CnRegionList,
+		"other_region_list": config.OtherRegionList,
+	} {
+		list := make([]string, 0)
+		if v != "" {
+			list = strings.Split(v, ",")
+		}
+		if err := d.Set(attribute, list); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return diags
+}
+
+// dcdnWafRuleConfig is the RuleConfig of a rule. Name and status only go
+// into BatchCreateDcdnWafRules, ModifyDcdnWafRule takes them separately.
+type dcdnWafRuleConfig struct {
+	Name            string                 `json:"name,omitempty"`
+	Status          string                 `json:"status,omitempty"`
+	Action          string                 `json:"action,omitempty"`
+	Conditions      []dcdnWafRuleCondition `json:"conditions,omitempty"`
+	CcStatus        int                    `json:"ccStatus,omitempty"`
+	Effect          string                 `json:"effect,omitempty"`
+	RateLimit       *dcdnWafRuleRateLimit  `json:"rateLimit,omitempty"`
+	WafGroupIds     string                 `json:"wafGroupIds,omitempty"`
+	RemoteAddr      string                 `json:"remoteAddr,omitempty"`
+	CnRegionList    string                 `json:"cnRegionList,omitempty"`
+	OtherRegionList string                 `json:"otherRegionList,omitempty"`
+}
+
+type dcdnWafRuleCondition struct {
+	Key     string `json:"key"`
+	SubKey  string `json:"subKey,omitempty"`
+	OpValue string `json:"opValue"`
+	Values  string `json:"values,omitempty"`
+}
+
+type dcdnWafRuleRateLimit struct {
+	Target    string                      `json:"target"`
+	SubKey    string                      `json:"subKey,omitempty"`
+	Interval  int                         `json:"interval"`
+	Threshold int                         `json:"threshold"`
+	Ttl       int                         `json:"ttl"`
+	Status    *dcdnWafRuleRateLimitStatus `json:"status,omitempty"`
+}
+
+type dcdnWafRuleRateLimitStatus struct {
+	Code  string `json:"code"`
+	Count int    `json:"count,omitempty"`
+	Ratio int    `json:"ratio,omitempty"`
+}
+
+// expandDcdnWafRuleConfig builds the RuleConfig of a rule. Custom ACL rules
+// with a rate_limit turn on rate limiting through ccStatus.
+func expandDcdnWafRuleConfig(d *schema.ResourceData) dcdnWafRuleConfig {
+	config := dcdnWafRuleConfig{
+		Action:          d.Get("action").(string),
+		WafGroupIds:     strings.Join(expandStringList(d.Get("waf_group_ids").([]interface{})), ","),
+		RemoteAddr:      strings.Join(expandStringList(d.Get("remote_addr").([]interface{})), ","),
+		CnRegionList:    strings.Join(expandStringList(d.Get("cn_region_list").([]interface{})), ","),
+		OtherRegionList: strings.Join(expandStringList(d.Get("other_region_list").([]interface{})), ","),
+	}
+
+	for _, v := range d.Get("conditions").([]interface{}) {
+		condition := v.(map[string]interface{})
+		config.Conditions = append(config.Conditions, dcdnWafRuleCondition{
+			Key:     condition["key"].(string),
+			SubKey:  condition["sub_key"].(string),
+			OpValue: condition["op_value"].(string),
+			Values:  condition["values"].(string),
+		})
+	}
+
+	if v := d.Get("rate_limit").([]interface{}); len(v) > 0 && v[0] != nil {
+		rateLimit := v[0].(map[string]interface{})
+		config.CcStatus = 1
+		config.Effect = "rule"
+		config.RateLimit = &dcdnWafRuleRateLimit{
+			Target:    rateLimit["target"].(string),
+			SubKey:    rateLimit["sub_key"].(string),
+			Interval:  rateLimit["interval"].(int),
+			Threshold: rateLimit["threshold"].(int),
+			Ttl:       rateLimit["ttl"].(int),
+		}
+		if code := rateLimit["status_code"].(string); code != "" {
+			config.RateLimit.Status = &dcdnWafRuleRateLimitStatus{
+				Code:  code,
+				Count: rateLimit["status_count"].(int),
+				Ratio: rateLimit["status_ratio"].(int),
+			}
+		}
+	}
+
+	return config
+}
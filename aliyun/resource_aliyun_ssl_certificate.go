@@ -0,0 +1,131 @@
+package aliyun
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"log"
+	"strings"
+	"time"
+)
+
+func resourceAliyunSslCertificate() *schema.Resource {
+	return &schema.Resource{
+		ReadContext:   resourceAliyunSslCertificateRead,
+		CreateContext: resourceAliyunSslCertificateCreate,
+		DeleteContext: resourceAliyunSslCertificateDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"cert": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validateCertificateChainPem,
+			},
+			"key": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				Sensitive:        true,
+				ValidateDiagFunc: validatePrivateKeyPem,
+				StateFunc:        sha256Hex,
+			},
+			"cert_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"common_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"subject_alt_names": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"issuer": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"not_after": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceAliyunSslCertificateDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).casconn
+
+	err := deleteCasCertificate(conn, d.Id())
+	if err != nil && !IsExpectedErrors(err, casCertificateNotFoundErrors) {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+func resourceAliyunSslCertificateCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn := m.(Client).casconn
+
+	certId, err := uploadCasCertificate(conn, d.Get("name").(string), d.Get("cert").(string), d.Get("key").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(certId)
+
+	return resourceAliyunSslCertificateRead(ctx, d, m)
+}
+
+func resourceAliyunSslCertificateRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	conn := m.(Client).casconn
+
+	res, err := describeCasCertificate(conn, d.Id())
+	if err != nil {
+		if IsExpectedErrors(err, casCertificateNotFoundErrors) {
+			log.Printf("[WARN] SSL certificate %s not found, removing from state", d.Id())
+			d.SetId("")
+			return diags
+		}
+		return diag.FromErr(err)
+	}
+
+	var sans []string
+	if res.Sans != "" {
+		sans = strings.Split(res.Sans, ",")
+	}
+
+	d.Set("name", res.Name)
+	d.Set("cert_id", d.Id())
+	d.Set("common_name", res.Common)
+	d.Set("issuer", res.Issuer)
+	d.Set("fingerprint", res.Fingerprint)
+	if err := d.Set("subject_alt_names", sans); err != nil {
+		return diag.FromErr(err)
+	}
+
+	cert, _ := parseCertificatePem(res.Cert)
+	if cert == nil {
+		cert, _ = parseCertificatePem(d.Get("cert").(string))
+	}
+	if cert != nil {
+		d.Set("not_after", cert.NotAfter.UTC().Format(time.RFC3339))
+	}
+
+	return diags
+}
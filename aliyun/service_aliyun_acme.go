@@ -0,0 +1,94 @@
+package aliyun
+
+import (
+	"context"
+	"fmt"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/alidns"
+	"net"
+	"strings"
+	"time"
+)
+
+// acmeDnsProvider publishes the TXT records answering ACME dns-01 challenges.
+type acmeDnsProvider interface {
+	// Present creates a TXT record for fqdn and returns a handle for CleanUp.
+	Present(fqdn, value string) (string, error)
+	CleanUp(handle string) error
+}
+
+// acmeDnsProviders maps the dns_provider argument of aliyun_acme_certificate
+// to a constructor. Tests can register a fake backend here.
+var acmeDnsProviders = map[string]func(m interface{}) (acmeDnsProvider, error){
+	"alidns": func(m interface{}) (acmeDnsProvider, error) {
+		return &alidnsAcmeDnsProvider{conn: m.(Client).alidnsconn}, nil
+	},
+}
+
+type alidnsAcmeDnsProvider struct {
+	conn *alidns.Client
+}
+
+func (p *alidnsAcmeDnsProvider) Present(fqdn, value string) (string, error) {
+	mainRequest := alidns.CreateGetMainDomainNameRequest()
+	mainRequest.InputString = strings.TrimSuffix(fqdn, ".")
+
+	mainRes, err := p.conn.GetMainDomainName(mainRequest)
+	if err != nil {
+		return "", err
+	}
+
+	request := alidns.CreateAddDomainRecordRequest()
+	request.DomainName = mainRes.DomainName
+	request.RR = mainRes.RR
+	request.Type = "TXT"
+	request.Value = value
+
+	res, err := p.conn.AddDomainRecord(request)
+	if err != nil {
+		return "", err
+	}
+
+	return res.RecordId, nil
+}
+
+func (p *alidnsAcmeDnsProvider) CleanUp(handle string) error {
+	request := alidns.CreateDeleteDomainRecordRequest()
+	request.RecordId = handle
+
+	_, err := p.conn.DeleteDomainRecord(request)
+	if IsExpectedErrors(err, []string{"DomainRecordNotBelongToUser"}) {
+		return nil
+	}
+
+	return err
+}
+
+// acmeLookupTxt checks that challenge records have propagated. Tests replace
+// it along with the DNS provider.
+var acmeLookupTxt = lookupTxt
+
+// lookupTxt resolves the TXT records of fqdn, through resolvers ("host:port")
+// when given and through the system resolver otherwise.
+func lookupTxt(ctx context.Context, fqdn string, resolvers []string) ([]string, error) {
+	if len(resolvers) == 0 {
+		return net.DefaultResolver.LookupTXT(ctx, fqdn)
+	}
+
+	var lastErr error
+	for _, server := range resolvers {
+		resolver := &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				dialer := net.Dialer{Timeout: 5 * time.Second}
+				return dialer.DialContext(ctx, network, server)
+			},
+		}
+		records, err := resolver.LookupTXT(ctx, fqdn)
+		if err == nil {
+			return records, nil
+		}
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("error looking up TXT records of %s: %s", fqdn, lastErr)
+}
@@ -0,0 +1,135 @@
+package aliyun
+
+import (
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/responses"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/cas"
+	"strconv"
+)
+
+// The cas package of the SDK version in use lacks the APIs managing uploaded
+// certificates, so their requests are declared here the way the SDK generates
+// them.
+
+var casCertificateNotFoundErrors = []string{"NotFound", "ResourceNotFound"}
+
+type casUploadUserCertificateRequest struct {
+	*requests.RpcRequest
+	Name string `position:"Query" name:"Name"`
+	Cert string `position:"Query" name:"Cert"`
+	Key  string `position:"Query" name:"Key"`
+}
+
+type casUploadUserCertificateResponse struct {
+	*responses.BaseResponse
+	RequestId string `json:"RequestId" xml:"RequestId"`
+	CertId    int64  `json:"CertId" xml:"CertId"`
+}
+
+type casGetUserCertificateDetailRequest struct {
+	*requests.RpcRequest
+	CertId requests.Integer `position:"Query" name:"CertId"`
+}
+
+type casGetUserCertificateDetailResponse struct {
+	*responses.BaseResponse
+	RequestId   string `json:"RequestId" xml:"RequestId"`
+	Id          int64  `json:"Id" xml:"Id"`
+	Name        string `json:"Name" xml:"Name"`
+	Common      string `json:"Common" xml:"Common"`
+	Sans        string `json:"Sans" xml:"Sans"`
+	Issuer      string `json:"Issuer" xml:"Issuer"`
+	Fingerprint string `json:"Fingerprint" xml:"Fingerprint"`
+	Cert        string `json:"Cert" xml:"Cert"`
+	Expired     bool   `json:"Expired" xml:"Expired"`
+}
+
+type casDeleteUserCertificateRequest struct {
+	*requests.RpcRequest
+	CertId requests.Integer `position:"Query" name:"CertId"`
+}
+
+type casListUserCertificateOrderResponse struct {
+	*responses.BaseResponse
+	RequestId            string                   `json:"RequestId" xml:"RequestId"`
+	TotalCount           int64                    `json:"TotalCount" xml:"TotalCount"`
+	CertificateOrderList []casUploadedCertificate `json:"CertificateOrderList" xml:"CertificateOrderList"`
+}
+
+// casUploadedCertificate is an entry of ListUserCertificateOrder with
+// OrderType UPLOAD.
+type casUploadedCertificate struct {
+	CertificateId int64  `json:"CertificateId" xml:"CertificateId"`
+	Name          string `json:"Name" xml:"Name"`
+	CommonName    string `json:"CommonName" xml:"CommonName"`
+	Sans          string `json:"Sans" xml:"Sans"`
+	Issuer        string `json:"Issuer" xml:"Issuer"`
+	Fingerprint   string `json:"Fingerprint" xml:"Fingerprint"`
+	CertEndTime   int64  `json:"CertEndTime" xml:"CertEndTime"`
+	Expired       bool   `json:"Expired" xml:"Expired"`
+}
+
+func uploadCasCertificate(conn *cas.Client, name, cert, key string) (string, error) {
+	request := &casUploadUserCertificateRequest{RpcRequest: &requests.RpcRequest{}}
+	request.InitWithApiInfo("cas", "2020-04-07", "UploadUserCertificate", "", "")
+	request.Method = requests.POST
+	request.Name = name
+	request.Cert = cert
+	request.Key = key
+
+	response := &casUploadUserCertificateResponse{BaseResponse: &responses.BaseResponse{}}
+	if err := conn.DoAction(request, response); err != nil {
+		return "", err
+	}
+
+	return strconv.FormatInt(response.CertId, 10), nil
+}
+
+func describeCasCertificate(conn *cas.Client, certId string) (*casGetUserCertificateDetailResponse, error) {
+	request := &casGetUserCertificateDetailRequest{RpcRequest: &requests.RpcRequest{}}
+	request.InitWithApiInfo("cas", "2020-04-07", "GetUserCertificateDetail", "", "")
+	request.Method = requests.POST
+	request.CertId = requests.Integer(certId)
+
+	response := &casGetUserCertificateDetailResponse{BaseResponse: &responses.BaseResponse{}}
+	if err := conn.DoAction(request, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func deleteCasCertificate(conn *cas.Client, certId string) error {
+	request := &casDeleteUserCertificateRequest{RpcRequest: &requests.RpcRequest{}}
+	request.InitWithApiInfo("cas", "2020-04-07", "DeleteUserCertificate", "", "")
+	request.Method = requests.POST
+	request.CertId = requests.Integer(certId)
+
+	return conn.DoAction(request, &responses.BaseResponse{})
+}
+
+// listCasCertificates returns every uploaded certificate matching keyword,
+// which CAS matches against the certificate name and domains.
+func listCasCertificates(conn *cas.Client, keyword string) ([]casUploadedCertificate, error) {
+	var certificates []casUploadedCertificate
+
+	request := cas.CreateListUserCertificateOrderRequest()
+	request.OrderType = "UPLOAD"
+	request.Keyword = keyword
+	request.ShowSize = requests.NewInteger(50)
+	for page := 1; ; page++ {
+		request.CurrentPage = requests.NewInteger(page)
+
+		response := &casListUserCertificateOrderResponse{BaseResponse: &responses.BaseResponse{}}
+		if err := conn.DoAction(request, response); err != nil {
+			return nil, err
+		}
+
+		certificates = append(certificates, response.CertificateOrderList...)
+		if len(response.CertificateOrderList) == 0 || int64(len(certificates)) >= response.TotalCount {
+			break
+		}
+	}
+
+	return certificates, nil
+}
@@ -0,0 +1,60 @@
+package aliyun
+
+import (
+	"context"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/cdn"
+	"strings"
+	"time"
+)
+
+func setCdnDomainFunctions(ctx context.Context, conn *cdn.Client, domain string, functions []map[string]interface{}, timeout time.Duration) ([]string, error) {
+	return setDomainFunctions(ctx, "cdn", domain, functions, func(functions string) error {
+		request := cdn.CreateBatchSetCdnDomainConfigRequest()
+		request.DomainNames = domain
+		request.Functions = functions
+
+		_, err := conn.BatchSetCdnDomainConfig(request)
+
+		return err
+	}, cdnDomainConfigs(conn), timeout)
+}
+
+func waitForCdnDomainConfigs(ctx context.Context, conn *cdn.Client, domain string, functions []map[string]interface{}, configIds []string, timeout time.Duration) error {
+	return waitForDomainConfigs(ctx, "cdn", domain, functions, configIds, cdnDomainConfigs(conn), timeout)
+}
+
+func describeCdnDomainConfigs(conn *cdn.Client, domain string, functionNames []string) ([]domainConfig, error) {
+	return describeDomainConfigs(domain, functionNames, cdnDomainConfigs(conn))
+}
+
+func cdnDomainConfigs(conn *cdn.Client) domainConfigsFunc {
+	return func(domain string, functionNames []string) ([]domainConfig, error) {
+		request := cdn.CreateDescribeCdnDomainConfigsRequest()
+		request.DomainName = domain
+		request.FunctionNames = strings.Join(functionNames, ",")
+
+		res, err := conn.DescribeCdnDomainConfigs(request)
+		if err != nil {
+			return nil, err
+		}
+
+		configs := make([]domainConfig, 0, len(res.DomainConfigs.DomainConfig))
+		for _, config := range res.DomainConfigs.DomainConfig {
+			args := make(map[string]string, len(config.FunctionArgs.FunctionArg))
+			for _, arg := range config.FunctionArgs.FunctionArg {
+				args[arg.ArgName] = arg.ArgValue
+			}
+			configs = append(configs, domainConfig{ConfigId: config.ConfigId, FunctionName: config.FunctionName, Status: config.Status, Args: args})
+		}
+
+		return configs, nil
+	}
+}
+
+func flattenCdnSources(v []cdn.SourceModel) []map[string]interface{} {
+	sources := make([]domainSource, 0, len(v))
+	for _, val := range v {
+		sources = append(sources, domainSource{Content: val.Content, Port: val.Port, Priority: val.Priority, Type: val.Type, Weight: val.Weight})
+	}
+	return flattenDomainSources(sources)
+}
@@ -0,0 +1,192 @@
+package aliyun
+
+import (
+	"context"
+	"fmt"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"strings"
+	"time"
+)
+
+func setDcdnDomainFunctions(ctx context.Context, conn *dcdn.Client, domain string, functions []map[string]interface{}, timeout time.Duration) ([]string, error) {
+	return setDomainFunctions(ctx, "dcdn", domain, functions, func(functions string) error {
+		request := dcdn.CreateBatchSetDcdnDomainConfigsRequest()
+		request.DomainNames = domain
+		request.Functions = functions
+
+		_, err := conn.BatchSetDcdnDomainConfigs(request)
+
+		return err
+	}, dcdnDomainConfigs(conn), timeout)
+}
+
+func waitForDcdnDomainConfigs(ctx context.Context, conn *dcdn.Client, domain string, functions []map[string]interface{}, configIds []string, timeout time.Duration) error {
+	return waitForDomainConfigs(ctx, "dcdn", domain, functions, configIds, dcdnDomainConfigs(conn), timeout)
+}
+
+func describeDcdnDomainConfigs(conn *dcdn.Client, domain string, functionNames []string) ([]domainConfig, error) {
+	return describeDomainConfigs(domain, functionNames, dcdnDomainConfigs(conn))
+}
+
+func dcdnDomainConfigs(conn *dcdn.Client) domainConfigsFunc {
+	return func(domain string, functionNames []string) ([]domainConfig, error) {
+		request := dcdn.CreateDescribeDcdnDomainConfigsRequest()
+		request.DomainName = domain
+		request.FunctionNames = strings.Join(functionNames, ",")
+
+		res, err := conn.DescribeDcdnDomainConfigs(request)
+		if err != nil {
+			return nil, err
+		}
+
+		configs := make([]domainConfig, 0, len(res.DomainConfigs.DomainConfig))
+		for _, config := range res.DomainConfigs.DomainConfig {
+			args := make(map[string]string, len(config.FunctionArgs.FunctionArg))
+			for _, arg := range config.FunctionArgs.FunctionArg {
+				args[arg.ArgName] = arg.ArgValue
+			}
+			configs = append(configs, domainConfig{ConfigId: config.ConfigId, FunctionName: config.FunctionName, Status: config.Status, Args: args})
+		}
+
+		return configs, nil
+	}
+}
+
+// The typed config resources only manage the configs listed in their
+// config_ids, the ones they wrote, and leave the other configs of their
+// functions alone.
+
+// describeTrackedDcdnDomainConfigs returns the configs of functionNames
+// listed in config_ids.
+func describeTrackedDcdnDomainConfigs(conn *dcdn.Client, d *schema.ResourceData, functionNames []string) ([]domainConfig, error) {
+	configs, err := describeDcdnDomainConfigs(conn, d.Id(), functionNames)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterDomainConfigs(configs, expandStringList(d.Get("config_ids").([]interface{}))), nil
+}
+
+// setTrackedDcdnDomainConfigs writes functions and deletes the configs in
+// obsolete, updating config_ids at each step so that a failure leaves no
+// config untracked, then waits for the written configs to be deployed.
+// Configs in kept are left as they are and stay in config_ids.
+func setTrackedDcdnDomainConfigs(ctx context.Context, conn *dcdn.Client, d *schema.ResourceData, functions []map[string]interface{}, kept, obsolete []string, timeout time.Duration) error {
+	var configIds []string
+	if len(functions) > 0 {
+		var err error
+		configIds, err = setDcdnDomainFunctions(ctx, conn, d.Id(), functions, timeout)
+		if err != nil {
+			return err
+		}
+	}
+
+	tracked := append(append([]string{}, configIds...), kept...)
+	if err := d.Set("config_ids", append(tracked, obsolete...)); err != nil {
+		return err
+	}
+	if err := deleteDcdnDomainConfigs(conn, d.Id(), obsolete); err != nil {
+		return err
+	}
+	if err := d.Set("config_ids", tracked); err != nil {
+		return err
+	}
+
+	if len(configIds) == 0 {
+		return nil
+	}
+
+	return waitForDcdnDomainConfigs(ctx, conn, d.Id(), functions, configIds, timeout)
+}
+
+// checkUntrackedDcdnDomainConfigs refuses to add a config of one of
+// functionNames, which a domain can only have one of, when the domain already
+// has one that is not in configIds.
+func checkUntrackedDcdnDomainConfigs(domain string, configs []domainConfig, configIds []string, functionNames []string) error {
+	tracked := make(map[string]bool, len(configIds))
+	for _, configId := range configIds {
+		tracked[configId] = true
+	}
+
+	for _, config := range configs {
+		if tracked[config.ConfigId] {
+			continue
+		}
+		for _, functionName := range functionNames {
+			if config.FunctionName == functionName {
+				return fmt.Errorf("dcdn config %s (%s) of %s is not managed by this resource, import the resource to manage it", config.FunctionName, config.ConfigId, domain)
+			}
+		}
+	}
+
+	return nil
+}
+
+// importDcdnDomainConfigs imports a typed config resource by domain, adopting
+// the configs of functionNames the domain has.
+func importDcdnDomainConfigs(functionNames []string) schema.StateContextFunc {
+	return func(_ context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+		configs, err := describeDcdnDomainConfigs(m.(Client).dcdnconn, d.Id(), functionNames)
+		if err != nil {
+			return nil, err
+		}
+
+		configIds := make([]string, 0, len(configs))
+		for _, config := range configs {
+			configIds = append(configIds, config.ConfigId)
+		}
+		if err := d.Set("config_ids", configIds); err != nil {
+			return nil, err
+		}
+
+		return []*schema.ResourceData{d}, nil
+	}
+}
+
+func deleteDcdnDomainConfigs(conn *dcdn.Client, domain string, configIds []string) error {
+	if len(configIds) == 0 {
+		return nil
+	}
+
+	request := dcdn.CreateDeleteDcdnSpecificConfigRequest()
+	request.DomainName = domain
+	request.ConfigId = strings.Join(configIds, ",")
+
+	_, err := conn.DeleteDcdnSpecificConfig(request)
+
+	return err
+}
+
+// reuseDcdnDomainConfigs pairs the desired argument sets of functionName with
+// its existing config ids in order, so existing rules are updated in place.
+// Ids left over are returned for deletion.
+func reuseDcdnDomainConfigs(functionName string, configIds []string, args []map[string]string) ([]map[string]interface{}, []string, error) {
+	functions := make([]map[string]interface{}, 0, len(args))
+	for i, arg := range args {
+		configId := ""
+		if i < len(configIds) {
+			configId = configIds[i]
+		}
+		function, err := domainFunction(functionName, configId, arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		functions = append(functions, function)
+	}
+
+	var obsolete []string
+	if len(configIds) > len(args) {
+		obsolete = configIds[len(args):]
+	}
+
+	return functions, obsolete, nil
+}
+
+func flattenDcdnSources(v []dcdn.Source) []map[string]interface{} {
+	sources := make([]domainSource, 0, len(v))
+	for _, val := range v {
+		sources = append(sources, domainSource{Content: val.Content, Port: val.Port, Priority: val.Priority, Type: val.Type, Weight: val.Weight})
+	}
+	return flattenDomainSources(sources)
+}
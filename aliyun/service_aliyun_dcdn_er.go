@@ -0,0 +1,169 @@
+package aliyun
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var dcdnRoutineNotFoundErrors = []string{"RoutineNotExist", "InvalidRoutine.NotFound"}
+
+// describeDcdnRoutine returns the Content of DescribeRoutine, or nil when the
+// routine does not exist.
+func describeDcdnRoutine(conn *dcdn.Client, name string) (map[string]interface{}, error) {
+	request := dcdn.CreateDescribeRoutineRequest()
+	request.Name = name
+
+	res, err := conn.DescribeRoutine(request)
+	if err != nil {
+		if IsExpectedErrors(err, dcdnRoutineNotFoundErrors) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return res.Content, nil
+}
+
+// uploadDcdnRoutineCode uploads code as the staging code of a routine and
+// commits it, returning the new code revision. The code itself is posted to
+// the OSS location that UploadStagingRoutineCode hands out.
+func uploadDcdnRoutineCode(ctx context.Context, conn *dcdn.Client, name, description string, code []byte) (string, error) {
+	uploadRequest := dcdn.CreateUploadStagingRoutineCodeRequest()
+	uploadRequest.Name = name
+	uploadRequest.CodeDescription = description
+
+	uploadRes, err := conn.UploadStagingRoutineCode(uploadRequest)
+	if err != nil {
+		return "", err
+	}
+
+	if err := postDcdnRoutineCode(ctx, uploadRes.Content, code); err != nil {
+		return "", fmt.Errorf("error uploading code of routine %s: %s", name, err)
+	}
+
+	commitRequest := dcdn.CreateCommitStagingRoutineCodeRequest()
+	commitRequest.Name = name
+	commitRequest.CodeDescription = description
+
+	commitRes, err := conn.CommitStagingRoutineCode(commitRequest)
+	if err != nil {
+		return "", err
+	}
+
+	revision := dcdnContentString(commitRes.Content["CodeRev"])
+	if revision == "" {
+		return "", fmt.Errorf("no code revision returned when committing code of routine %s", name)
+	}
+
+	return revision, nil
+}
+
+func postDcdnRoutineCode(ctx context.Context, content map[string]interface{}, code []byte) error {
+	url, _ := content["Url"].(string)
+	if url == "" {
+		return fmt.Errorf("no upload URL returned")
+	}
+
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	for field, key := range map[string]string{
+		"OSSAccessKeyId":       "OSSAccessKeyId",
+		"policy":               "OSSPolicy",
+		"Signature":            "OSSSignature",
+		"key":                  "Key",
+		"callback":             "OSSCallback",
+		"x-oss-security-token": "XOssSecurityToken",
+	} {
+		if v, ok := content[key].(string); ok && v != "" {
+			if err := form.WriteField(field, v); err != nil {
+				return err
+			}
+		}
+	}
+	file, err := form.CreateFormFile("file", "code.js")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(code); err != nil {
+		return err
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", form.FormDataContentType())
+
+	res, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("%s: %s", res.Status, strings.TrimSpace(string(message)))
+	}
+
+	return nil
+}
+
+// publishDcdnRoutineCode publishes a code revision to envs, each of staging
+// or production.
+func publishDcdnRoutineCode(conn *dcdn.Client, name, revision string, envs []string) error {
+	if len(envs) == 0 {
+		return nil
+	}
+
+	v, err := json.Marshal(envs)
+	if err != nil {
+		return err
+	}
+
+	request := dcdn.CreatePublishRoutineCodeRevisionRequest()
+	request.Name = name
+	request.SelectCodeRevision = revision
+	request.Envs = string(v)
+
+	_, err = conn.PublishRoutineCodeRevision(request)
+	return err
+}
+
+// dcdnRoutineEnvCodeRevision returns the code revision an environment of a
+// routine currently serves, as reported by DescribeRoutine.
+func dcdnRoutineEnvCodeRevision(routine map[string]interface{}, env string) string {
+	envs, _ := routine["Envs"].([]interface{})
+	for _, v := range envs {
+		e, ok := v.(map[string]interface{})
+		if !ok || e["Env"] != env {
+			continue
+		}
+		return dcdnContentString(e["CodeRev"])
+	}
+	return ""
+}
+
+// dcdnContentString formats a value of the untyped Content returned by the
+// routine APIs, where revisions come back as JSON numbers.
+func dcdnContentString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
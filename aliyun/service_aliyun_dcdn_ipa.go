@@ -0,0 +1,52 @@
+package aliyun
+
+import (
+	"context"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
+	"strings"
+	"time"
+)
+
+func setDcdnIpaDomainFunctions(ctx context.Context, conn *dcdn.Client, domain string, functions []map[string]interface{}, timeout time.Duration) ([]string, error) {
+	return setDomainFunctions(ctx, "dcdn ipa", domain, functions, func(functions string) error {
+		request := dcdn.CreateBatchSetDcdnIpaDomainConfigsRequest()
+		request.DomainNames = domain
+		request.Functions = functions
+
+		_, err := conn.BatchSetDcdnIpaDomainConfigs(request)
+
+		return err
+	}, dcdnIpaDomainConfigs(conn), timeout)
+}
+
+func waitForDcdnIpaDomainConfigs(ctx context.Context, conn *dcdn.Client, domain string, functions []map[string]interface{}, configIds []string, timeout time.Duration) error {
+	return waitForDomainConfigs(ctx, "dcdn ipa", domain, functions, configIds, dcdnIpaDomainConfigs(conn), timeout)
+}
+
+func describeDcdnIpaDomainConfigs(conn *dcdn.Client, domain string, functionNames []string) ([]domainConfig, error) {
+	return describeDomainConfigs(domain, functionNames, dcdnIpaDomainConfigs(conn))
+}
+
+func dcdnIpaDomainConfigs(conn *dcdn.Client) domainConfigsFunc {
+	return func(domain string, functionNames []string) ([]domainConfig, error) {
+		request := dcdn.CreateDescribeDcdnIpaDomainConfigsRequest()
+		request.DomainName = domain
+		request.FunctionNames = strings.Join(functionNames, ",")
+
+		res, err := conn.DescribeDcdnIpaDomainConfigs(request)
+		if err != nil {
+			return nil, err
+		}
+
+		configs := make([]domainConfig, 0, len(res.DomainConfigs.DomainConfig))
+		for _, config := range res.DomainConfigs.DomainConfig {
+			args := make(map[string]string, len(config.FunctionArgs.FunctionArg))
+			for _, arg := range config.FunctionArgs.FunctionArg {
+				args[arg.ArgName] = arg.ArgValue
+			}
+			configs = append(configs, domainConfig{ConfigId: config.ConfigId, FunctionName: config.FunctionName, Status: config.Status, Args: args})
+		}
+
+		return configs, nil
+	}
+}
@@ -0,0 +1,149 @@
+package aliyun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/dcdn"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var dcdnWafNotFoundErrors = []string{"InvalidPolicyId.NotFound", "InvalidRuleId.NotFound", "InvalidDomain.NotFound"}
+
+var dcdnWafDefenseScenes = []string{"waf_group", "custom_acl", "whitelist", "ip_blacklist", "region_block", "bot"}
+
+// describeDcdnWafPolicy returns the policy, or nil when it does not exist.
+func describeDcdnWafPolicy(conn *dcdn.Client, policyId string) (*dcdn.Policy, error) {
+	request := dcdn.CreateDescribeDcdnWafPolicyRequest()
+	request.PolicyId = requests.Integer(policyId)
+
+	res, err := conn.DescribeDcdnWafPolicy(request)
+	if err != nil {
+		if IsExpectedErrors(err, dcdnWafNotFoundErrors) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if res.Policy.PolicyId == 0 {
+		return nil, nil
+	}
+
+	return &res.Policy, nil
+}
+
+// describeDcdnWafDomainPolicyIds returns the sorted ids of the policies bound
+// to domain, or nil when the domain is not protected by WAF.
+func describeDcdnWafDomainPolicyIds(conn *dcdn.Client, domain string) ([]string, error) {
+	request := dcdn.CreateDescribeDcdnWafDomainDetailRequest()
+	request.DomainName = domain
+
+	res, err := conn.DescribeDcdnWafDomainDetail(request)
+	if err != nil {
+		if IsExpectedErrors(err, dcdnWafNotFoundErrors) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if res.Domain.DomainName == "" {
+		return nil, nil
+	}
+
+	policyIds := make([]string, 0, len(res.Domain.DefenseScenes))
+	for _, scene := range res.Domain.DefenseScenes {
+		if scene.PolicyId != 0 {
+			policyIds = append(policyIds, strconv.FormatInt(scene.PolicyId, 10))
+		}
+	}
+	sort.Strings(policyIds)
+
+	return policyIds, nil
+}
+
+// describeDcdnWafDomainClientIpTag returns the client_ip_tag of domain, which
+// DescribeDcdnWafDomainDetail does not report.
+func describeDcdnWafDomainClientIpTag(conn *dcdn.Client, domain string) (string, error) {
+	queryArgs, err := json.Marshal(map[string]string{"DomainName": domain})
+	if err != nil {
+		return "", err
+	}
+
+	request := dcdn.CreateDescribeDcdnWafDomainsRequest()
+	request.QueryArgs = string(queryArgs)
+	request.PageSize = requests.NewInteger(100)
+
+	for page := 1; ; page++ {
+		request.PageNumber = requests.NewInteger(page)
+
+		res, err := conn.DescribeDcdnWafDomains(request)
+		if err != nil {
+			return "", err
+		}
+
+		for _, item := range res.Domains {
+			if item.DomainName == domain {
+				return item.ClientIpTag, nil
+			}
+		}
+		if len(res.Domains) == 0 || page*100 >= res.TotalCount {
+			return "", nil
+		}
+	}
+}
+
+// waitForDcdnWafDomainPolicies waits until the policies in bound are bound to
+// domain and those in unbound are not, binding changes taking a moment to be
+// reported. Other policies bound to the domain are left out of the check.
+func waitForDcdnWafDomainPolicies(ctx context.Context, conn *dcdn.Client, domain string, bound, unbound []string, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"binding"},
+		Target:  []string{"bound"},
+		Refresh: func() (interface{}, string, error) {
+			current, err := describeDcdnWafDomainPolicyIds(conn, domain)
+			if err != nil {
+				return nil, "", err
+			}
+			isBound := make(map[string]bool, len(current))
+			for _, policyId := range current {
+				isBound[policyId] = true
+			}
+			for _, policyId := range bound {
+				if !isBound[policyId] {
+					return current, "binding", nil
+				}
+			}
+			for _, policyId := range unbound {
+				if isBound[policyId] {
+					return current, "binding", nil
+				}
+			}
+			return current, "bound", nil
+		},
+		Timeout:    timeout,
+		MinTimeout: 3 * time.Second,
+	}
+
+	_, err := stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		if _, ok := err.(*resource.TimeoutError); ok {
+			return fmt.Errorf("timeout waiting for dcdn waf policies %s to be bound to %s and %s to be unbound", strings.Join(bound, ","), domain, strings.Join(unbound, ","))
+		}
+		return fmt.Errorf("error waiting for dcdn waf policies of %s: %s", domain, err)
+	}
+
+	return nil
+}
+
+func modifyDcdnWafPolicyDomains(conn *dcdn.Client, policyId, bind, unbind string) error {
+	request := dcdn.CreateModifyDcdnWafPolicyDomainsRequest()
+	request.PolicyId = requests.Integer(policyId)
+	request.BindDomains = bind
+	request.UnbindDomains = unbind
+
+	_, err := conn.ModifyDcdnWafPolicyDomains(request)
+	return err
+}